@@ -0,0 +1,198 @@
+package flatfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/arkinjo/TogoProt2/pkg/uniprot"
+)
+
+// featureTypes maps the feature keys of FT lines to the feature types of
+// the XML.
+var featureTypes = map[string]string{
+	"ACT_SITE": "active site",
+	"BINDING":  "binding site",
+	"CA_BIND":  "calcium-binding region",
+	"CARBOHYD": "glycosylation site",
+	"CHAIN":    "chain",
+	"COILED":   "coiled-coil region",
+	"COMPBIAS": "compositionally biased region",
+	"CONFLICT": "sequence conflict",
+	"CROSSLNK": "cross-link",
+	"DISULFID": "disulfide bond",
+	"DNA_BIND": "DNA-binding region",
+	"DOMAIN":   "domain",
+	"HELIX":    "helix",
+	"INIT_MET": "initiator methionine",
+	"INTRAMEM": "intramembrane region",
+	"LIPID":    "lipid moiety-binding region",
+	"MOD_RES":  "modified residue",
+	"MOTIF":    "short sequence motif",
+	"MUTAGEN":  "mutagenesis site",
+	"NON_CONS": "non-consecutive residues",
+	"NON_STD":  "non-standard amino acid",
+	"NON_TER":  "non-terminal residue",
+	"NP_BIND":  "nucleotide phosphate-binding region",
+	"PEPTIDE":  "peptide",
+	"PROPEP":   "propeptide",
+	"REGION":   "region of interest",
+	"REPEAT":   "repeat",
+	"SIGNAL":   "signal peptide",
+	"SITE":     "site",
+	"STRAND":   "strand",
+	"TOPO_DOM": "topological domain",
+	"TRANSIT":  "transit peptide",
+	"TRANSMEM": "transmembrane region",
+	"TURN":     "turn",
+	"UNSURE":   "unsure residue",
+	"VAR_SEQ":  "splice variant",
+	"VARIANT":  "sequence variant",
+	"ZN_FING":  "zinc finger region",
+}
+
+// changeFeatures are the feature types whose note describes a change of
+// sequence, such as "V -> E (in dbSNP:rs123)".
+var changeFeatures = map[string]bool{
+	"sequence conflict": true,
+	"mutagenesis site":  true,
+	"sequence variant":  true,
+	"splice variant":    true,
+}
+
+// parseFeatures reads the feature table. Each feature starts with a line
+// holding its key in columns 6-21 and its location after that, followed by
+// /qualifier="value" lines whose quoted values may wrap. The note becomes
+// the description and the id the feature ID; evidence is dropped.
+func parseFeatures(texts []string) ([]uniprot.Feature, error) {
+	var features []uniprot.Feature
+	var name, value string
+	open := false
+	flush := func() {
+		if name == "" {
+			return
+		}
+		f := &features[len(features)-1]
+		value = strings.Trim(value, `"`)
+		switch name {
+		case "note":
+			if changeFeatures[f.Type] {
+				parseChange(f, value)
+			} else {
+				f.Description = value
+			}
+		case "id":
+			f.Id = value
+		}
+		name = ""
+	}
+
+	for _, t := range texts {
+		key := strings.TrimSpace(t[:min(16, len(t))])
+		rest := ""
+		if len(t) > 16 {
+			rest = strings.TrimSpace(t[16:])
+		}
+		switch {
+		case key != "":
+			flush()
+			f, err := newFeature(key, rest)
+			if err != nil {
+				return features, err
+			}
+			features = append(features, f)
+		case len(features) == 0:
+			return features, fmt.Errorf("qualifier before feature key")
+		case open:
+			value += " " + rest
+			open = !strings.HasSuffix(rest, `"`)
+		case strings.HasPrefix(rest, "/"):
+			flush()
+			name, value, _ = strings.Cut(rest[1:], "=")
+			open = strings.HasPrefix(value, `"`) && (len(value) == 1 || !strings.HasSuffix(value, `"`))
+		}
+	}
+	flush()
+	return features, nil
+}
+
+// newFeature returns the feature with the given key and location.
+func newFeature(key, location string) (uniprot.Feature, error) {
+	f := uniprot.Feature{Type: featureTypes[key]}
+	if f.Type == "" {
+		f.Type = strings.ToLower(key)
+	}
+	// A location on another sequence is prefixed with its identifier, as
+	// in "P12345-2:10..20".
+	if i := strings.LastIndexByte(location, ':'); i >= 0 {
+		f.Ref, location = location[:i], location[i+1:]
+	}
+	begin, end, ranged := strings.Cut(location, "..")
+	var err error
+	if !ranged {
+		f.Location.Position.Value, f.Location.Position.Status, err = parsePosition(begin)
+		return f, err
+	}
+	if f.Location.Begin.Position, f.Location.Begin.Status, err = parsePosition(begin); err != nil {
+		return f, err
+	}
+	f.Location.End.Position, f.Location.End.Status, err = parsePosition(end)
+	return f, err
+}
+
+// parsePosition reads a position such as "42", "<1", ">50", "?12" or "?"
+// and returns it with its XML status.
+func parsePosition(s string) (int, string, error) {
+	status := ""
+	switch {
+	case s == "?":
+		return 0, "unknown", nil
+	case strings.HasPrefix(s, "<"):
+		status, s = "less than", s[1:]
+	case strings.HasPrefix(s, ">"):
+		status, s = "greater than", s[1:]
+	case strings.HasPrefix(s, "?"):
+		status, s = "uncertain", s[1:]
+	}
+	pos, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid feature position %q", s)
+	}
+	return pos, status, nil
+}
+
+// parseChange splits a note such as "V -> E (in dbSNP:rs123)",
+// "K->A: Loss of activity." or "Missing (in isoform 2)" into the original
+// and variant residues and the description. For sequence conflicts, the
+// reference number of "in Ref. 3" is stored as the feature's ref. Notes of
+// another form are kept whole as the description.
+func parseChange(f *uniprot.Feature, note string) {
+	change, desc := note, ""
+	if i := strings.Index(note, " ("); i >= 0 && strings.HasSuffix(note, ")") {
+		change, desc = note[:i], note[i+2:len(note)-1]
+	} else if i := strings.Index(note, ": "); i >= 0 {
+		change, desc = note[:i], note[i+2:]
+	}
+	original, variants, ok := strings.Cut(change, "->")
+	switch {
+	case ok:
+		f.Original = strings.ReplaceAll(original, " ", "")
+		for _, v := range strings.Split(variants, ",") {
+			f.Variation = append(f.Variation, uniprot.Variation{Sequence: strings.ReplaceAll(v, " ", "")})
+		}
+	case change != "Missing":
+		f.Description = note
+		return
+	}
+	f.Description = desc
+
+	if f.Type == "sequence conflict" && f.Ref == "" {
+		if _, rest, ok := strings.Cut(desc, "Ref. "); ok {
+			n := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+			if n < 0 {
+				n = len(rest)
+			}
+			f.Ref = rest[:n]
+		}
+	}
+}
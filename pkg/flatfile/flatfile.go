@@ -0,0 +1,215 @@
+// Package flatfile reads UniProtKB entries in the line-oriented flat-file
+// format (uniprot_sprot.dat, uniprot_trembl.dat) into the uniprot.Entry
+// model, so that code written against the XML reader works unchanged.
+//
+// The flat file carries less structure than the XML: evidence is dropped,
+// comments keep their text only, keywords have no IDs and cross-reference
+// properties are named only for the common databases.
+package flatfile
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+
+	"github.com/arkinjo/TogoProt2/pkg/uniprot"
+)
+
+// errStop is returned by eachEntry when the callback asks to stop.
+var errStop = errors.New("flatfile: iteration stopped")
+
+// maxLineSize bounds the length of a line; flat-file lines are normally
+// at most 80 characters.
+const maxLineSize = 1 << 20
+
+// FlatEntries returns an iterator over the entries of a UniProtKB flat
+// file, optionally gzipped. Errors opening or parsing the file are yielded
+// and end the iteration.
+func FlatEntries(filePath string) iter.Seq2[uniprot.Entry, error] {
+	return func(yield func(uniprot.Entry, error) bool) {
+		r, err := uniprot.Open(filePath)
+		if err != nil {
+			yield(uniprot.Entry{}, err)
+			return
+		}
+		defer r.Close()
+
+		err = eachEntry(r, func(entry uniprot.Entry) bool {
+			return yield(entry, nil)
+		})
+		if err != nil && err != errStop {
+			yield(uniprot.Entry{}, fmt.Errorf("decoding %q: %w", filePath, err))
+		}
+	}
+}
+
+// line is a line of an entry split into its two-letter code and the text
+// from column 6 on. Sequence lines have the code "  ".
+type line struct {
+	code string
+	text string
+}
+
+// eachEntry parses the entries in r, each terminated by a "//" line, and
+// calls fn for each of them. It returns nil at the end of input, errStop if
+// fn returns false, and the first read or parse error otherwise.
+func eachEntry(r io.Reader, fn func(uniprot.Entry) bool) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	var lines []line
+	start := 0
+	for n := 1; scanner.Scan(); n++ {
+		text := scanner.Text()
+		if text == "//" {
+			entry, err := parseEntry(lines)
+			if err != nil {
+				return fmt.Errorf("entry at line %d: %w", start, err)
+			}
+			if !fn(entry) {
+				return errStop
+			}
+			lines = lines[:0]
+			continue
+		}
+		if len(lines) == 0 {
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			start = n
+		}
+		l := line{code: text[:min(2, len(text))]}
+		if len(text) > 5 {
+			l.text = text[5:]
+		}
+		lines = append(lines, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if len(lines) > 0 {
+		return fmt.Errorf("entry at line %d: missing terminating //", start)
+	}
+	return nil
+}
+
+// parseEntry builds an entry from its lines. Consecutive lines with the
+// same code are handled together, since most fields wrap over several
+// lines; reference lines (RN, RP, RX, ...) apply to the reference opened by
+// the last RN line.
+func parseEntry(lines []line) (uniprot.Entry, error) {
+	var e uniprot.Entry
+	var seq strings.Builder
+	for i := 0; i < len(lines); {
+		code := lines[i].code
+		j := i + 1
+		for j < len(lines) && lines[j].code == code {
+			j++
+		}
+		texts := make([]string, j-i)
+		for k := range texts {
+			texts[k] = lines[i+k].text
+		}
+		i = j
+
+		var err error
+		switch code {
+		case "ID":
+			parseID(&e, texts[0])
+		case "AC":
+			e.Accession = append(e.Accession, splitList(join(texts), ";")...)
+		case "DT":
+			for _, t := range texts {
+				if err = parseDT(&e, t); err != nil {
+					break
+				}
+			}
+		case "DE":
+			parseDE(&e, texts)
+		case "GN":
+			parseGN(&e, texts)
+		case "OS":
+			e.Organism.Name = organismNames(join(texts))
+		case "OG":
+			parseOG(&e, join(texts))
+		case "OC":
+			for _, taxon := range splitList(strings.TrimSuffix(join(texts), "."), ";") {
+				e.Organism.Lineage.Taxon = append(e.Organism.Lineage.Taxon, uniprot.Taxon{Value: taxon})
+			}
+		case "OX":
+			e.Organism.DbReference = append(e.Organism.DbReference, taxonomyRefs(join(texts))...)
+		case "OH":
+			for _, t := range texts {
+				e.OrganismHost = append(e.OrganismHost, organismHost(t))
+			}
+		case "RN", "RP", "RC", "RX", "RG", "RA", "RT", "RL":
+			err = parseReference(&e, code, texts)
+		case "CC":
+			parseCC(&e, texts)
+		case "DR":
+			for _, t := range texts {
+				e.DbReference = append(e.DbReference, parseDR(t))
+			}
+		case "PE":
+			_, level, _ := strings.Cut(texts[0], ":")
+			e.ProteinExistence.Type = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(level), ";"))
+		case "KW":
+			for _, kw := range splitList(strings.TrimSuffix(join(texts), "."), ";") {
+				e.Keyword = append(e.Keyword, uniprot.Keyword{Value: kw})
+			}
+		case "FT":
+			e.Feature, err = parseFeatures(texts)
+		case "SQ":
+			err = parseSQ(&e, texts[0])
+		case "  ":
+			for _, t := range texts {
+				seq.WriteString(strings.ReplaceAll(t, " ", ""))
+			}
+		}
+		if err != nil {
+			return e, fmt.Errorf("%s line: %w", code, err)
+		}
+	}
+	e.Sequence.Value = seq.String()
+	return e, nil
+}
+
+// join joins the texts of a multi-line field with single spaces.
+func join(texts []string) string {
+	for i, t := range texts {
+		texts[i] = strings.TrimSpace(t)
+	}
+	return strings.Join(texts, " ")
+}
+
+// splitList splits s at sep and returns the non-empty items, trimmed and
+// without evidence.
+func splitList(s, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(s, sep) {
+		if item = stripEvidence(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// stripEvidence removes the evidence tags in braces, such as
+// "{ECO:0000269|PubMed:123}", from s and normalizes its white space.
+func stripEvidence(s string) string {
+	for {
+		i := strings.IndexByte(s, '{')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			break
+		}
+		s = s[:i] + s[i+j+1:]
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	return strings.TrimSuffix(s, " .")
+}
@@ -0,0 +1,152 @@
+package flatfile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/arkinjo/TogoProt2/pkg/uniprot"
+)
+
+// The testdata files hold HBA_HUMAN (P69905) in both formats, abridged from
+// a UniProtKB release. The last three REGION features were added to cover
+// fuzzy and unknown positions.
+const (
+	datFile = "testdata/P69905.dat"
+	xmlFile = "testdata/P69905.xml"
+)
+
+// readOne returns the single entry of seq.
+func readOne(t *testing.T, seq func(func(uniprot.Entry, error) bool)) uniprot.Entry {
+	t.Helper()
+	var entries []uniprot.Entry
+	for e, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("read %d entries, want 1", len(entries))
+	}
+	return entries[0]
+}
+
+// comparable clears the parts of an entry that the flat file does not
+// carry: evidence, keyword IDs, comment fields other than type, molecule
+// and text, the GO evidence and project properties, and the raw RL line.
+func comparable(e uniprot.Entry) uniprot.Entry {
+	e.Evidence = nil
+	for i, k := range e.Keyword {
+		e.Keyword[i] = uniprot.Keyword{Value: k.Value}
+	}
+	for i, c := range e.Comment {
+		comment := uniprot.Comment{Type: c.Type}
+		comment.Molecule.Value = c.Molecule.Value
+		for _, t := range c.Text {
+			comment.Text = append(comment.Text, uniprot.Text{Value: t.Value})
+		}
+		e.Comment[i] = comment
+	}
+	for i := range e.Reference {
+		e.Reference[i].Citation.Journal = uniprot.Journal{}
+	}
+	for i, ref := range e.DbReference {
+		if ref.Type == "GO" {
+			e.DbReference[i].Property = ref.Property[:1]
+		}
+	}
+	for i := range e.Feature {
+		e.Feature[i].EvidenceKeys = ""
+	}
+	return e
+}
+
+func TestFlatEntriesMatchXML(t *testing.T) {
+	flat := readOne(t, FlatEntries(datFile))
+	xml := readOne(t, uniprot.UniProtEntries(xmlFile))
+
+	// Canonical renders one field per line, so a difference is reported
+	// by the lines that differ.
+	got := strings.Split(comparable(flat).Canonical(), "\n")
+	want := strings.Split(comparable(xml).Canonical(), "\n")
+	if len(got) != len(want) {
+		t.Errorf("flat entry renders %d lines, XML entry %d", len(got), len(want))
+	}
+	for i := range min(len(got), len(want)) {
+		if got[i] != want[i] {
+			t.Errorf("line %d:\n flat %s\n  xml %s", i+1, got[i], want[i])
+		}
+	}
+
+}
+
+func TestFlatEntriesDetails(t *testing.T) {
+	e := readOne(t, FlatEntries(datFile))
+
+	// The wrapped note is joined with a single space.
+	variant := e.Feature[6]
+	if want := "in Chongqing; O(2) affinity up; increased stability against autoxidation; dbSNP:rs28928878"; variant.Description != want {
+		t.Errorf("variant description %q, want %q", variant.Description, want)
+	}
+	if variant.MutationString() != "L3R" || variant.Id != "VAR_002720" {
+		t.Errorf("variant %s %s, want L3R VAR_002720", variant.MutationString(), variant.Id)
+	}
+	if conflict := e.Feature[7]; conflict.Ref != "2" || conflict.ConflictString() != "N10K (Ref.2)" {
+		t.Errorf("conflict %q with ref %q", conflict.ConflictString(), conflict.Ref)
+	}
+
+	var locations []string
+	for _, f := range e.Feature[8:] {
+		l := f.Location
+		locations = append(locations, fmt.Sprintf("%s:%d..%s:%d", l.Begin.Status, l.Begin.Position, l.End.Status, l.End.Position))
+	}
+	want := "less than:1..greater than:15 uncertain:30..uncertain:40 :120..unknown:0"
+	if got := strings.Join(locations, " "); got != want {
+		t.Errorf("fuzzy locations %q, want %q", got, want)
+	}
+
+	// The component named after "Contains:" is not taken for a protein name.
+	if n := len(e.Protein.AlternativeName); n != 2 || e.Protein.RecommendedName.FullName.Value != "Hemoglobin subunit alpha" {
+		t.Errorf("protein names %+v", e.Protein)
+	}
+	if len(e.Gene) != 2 || e.GeneName() != "HBA1" || e.Gene[1].Name[0].Value != "HBA2" {
+		t.Errorf("genes %+v, want HBA1 and HBA2", e.Gene)
+	}
+	if c := e.Comment[1]; c.Molecule.Value != "Hemopressin" || !strings.HasPrefix(c.Text[0].Value, "Hemopressin acts") {
+		t.Errorf("comment on a component: %+v", c)
+	}
+	if len(e.Comment) != 4 {
+		t.Errorf("%d comments, want 4 without the copyright", len(e.Comment))
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		in     string
+		pos    int
+		status string
+	}{
+		{"42", 42, ""},
+		{"<1", 1, "less than"},
+		{">50", 50, "greater than"},
+		{"?12", 12, "uncertain"},
+		{"?", 0, "unknown"},
+	}
+	for _, tt := range tests {
+		pos, status, err := parsePosition(tt.in)
+		if err != nil || pos != tt.pos || status != tt.status {
+			t.Errorf("parsePosition(%q) = %d, %q, %v; want %d, %q", tt.in, pos, status, err, tt.pos, tt.status)
+		}
+	}
+	if _, _, err := parsePosition("x1"); err == nil {
+		t.Error("parsePosition(\"x1\") succeeded")
+	}
+}
+
+func TestMissingTerminator(t *testing.T) {
+	err := eachEntry(strings.NewReader("ID   X_HUMAN   Reviewed;   1 AA.\nAC   P1;\n"), func(uniprot.Entry) bool { return true })
+	if err == nil || !strings.Contains(err.Error(), "missing terminating //") {
+		t.Errorf("error %v, want missing terminating //", err)
+	}
+}
@@ -0,0 +1,530 @@
+package flatfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/arkinjo/TogoProt2/pkg/uniprot"
+)
+
+// parseID reads the entry name and review status from the ID line,
+// e.g. "HBA_HUMAN   Reviewed;   142 AA.".
+func parseID(e *uniprot.Entry, text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return
+	}
+	e.Name = append(e.Name, uniprot.Name{Value: fields[0]})
+	if len(fields) > 1 {
+		switch strings.TrimSuffix(fields[1], ";") {
+		case "Reviewed":
+			e.Dataset = "Swiss-Prot"
+		case "Unreviewed":
+			e.Dataset = "TrEMBL"
+		}
+	}
+}
+
+// flatDate converts a flat-file date such as "21-JUL-1986" to the
+// "1986-07-21" form used in the XML.
+func flatDate(s string) (string, error) {
+	t, err := time.Parse("02-Jan-2006", s)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(time.DateOnly), nil
+}
+
+// parseDT reads a DT line such as "23-JAN-2007, sequence version 2.".
+func parseDT(e *uniprot.Entry, text string) error {
+	date, event, _ := strings.Cut(strings.TrimSpace(text), ", ")
+	date, err := flatDate(date)
+	if err != nil {
+		return err
+	}
+	event = strings.TrimSuffix(event, ".")
+	switch {
+	case strings.HasPrefix(event, "integrated into"):
+		e.Created = date
+	case strings.HasPrefix(event, "sequence version "):
+		e.Sequence.Modified = date
+		e.Sequence.Version, err = strconv.Atoi(strings.TrimPrefix(event, "sequence version "))
+	case strings.HasPrefix(event, "entry version "):
+		e.Modified = date
+		e.Version, err = strconv.Atoi(strings.TrimPrefix(event, "entry version "))
+	}
+	return err
+}
+
+// parseDE reads the protein names. Names of the components listed after
+// "Contains:" or "Includes:" are skipped, as the Entry model has no place
+// for them; the trailing "Flags:" line sets the precursor and fragment
+// attributes of the sequence.
+func parseDE(e *uniprot.Entry, texts []string) {
+	var full *uniprot.FullName
+	var short *[]uniprot.ShortName
+	var ec *[]string
+	skip := false
+	for _, t := range texts {
+		t = strings.TrimSpace(t)
+		category, rest, ok := strings.Cut(t, ":")
+		if !ok || strings.Contains(category, "=") {
+			category, rest = "", t
+		}
+		rest = strings.TrimSpace(rest)
+		switch category {
+		case "Contains", "Includes":
+			skip = true
+			continue
+		case "Flags":
+			for _, flag := range splitList(rest, ";") {
+				switch flag {
+				case "Precursor":
+					e.Sequence.Precursor = true
+				case "Fragment":
+					e.Sequence.Fragment = "single"
+				case "Fragments":
+					e.Sequence.Fragment = "multiple"
+				}
+			}
+			continue
+		}
+		if skip {
+			continue
+		}
+
+		switch category {
+		case "RecName":
+			name := &e.Protein.RecommendedName
+			full, short, ec = &name.FullName, &name.ShortName, &name.EcNumber
+		case "AltName":
+			e.Protein.AlternativeName = append(e.Protein.AlternativeName, uniprot.AlternativeName{})
+			name := &e.Protein.AlternativeName[len(e.Protein.AlternativeName)-1]
+			full, short, ec = &name.FullName, &name.ShortName, &name.EcNumber
+		case "SubName":
+			e.Protein.SubmittedName = append(e.Protein.SubmittedName, uniprot.SubmittedName{})
+			name := &e.Protein.SubmittedName[len(e.Protein.SubmittedName)-1]
+			full, short, ec = &name.FullName, &name.ShortName, &name.EcNumber
+		}
+		if full == nil {
+			continue
+		}
+		for _, item := range splitList(rest, ";") {
+			key, value, _ := strings.Cut(item, "=")
+			switch key {
+			case "Full":
+				full.Value = value
+			case "Short":
+				*short = append(*short, uniprot.ShortName{Value: value})
+			case "EC":
+				*ec = append(*ec, value)
+			}
+		}
+	}
+}
+
+// geneNameTypes maps the keys of GN lines to the gene name types of the
+// XML.
+var geneNameTypes = map[string]string{
+	"Name":              "primary",
+	"Synonyms":          "synonym",
+	"OrderedLocusNames": "ordered locus",
+	"ORFNames":          "ORF",
+}
+
+// parseGN reads the gene names. Genes are separated by "and" lines.
+func parseGN(e *uniprot.Entry, texts []string) {
+	var genes [][]string
+	current := []string{}
+	for _, t := range texts {
+		if strings.TrimSpace(t) == "and" {
+			genes = append(genes, current)
+			current = []string{}
+			continue
+		}
+		current = append(current, t)
+	}
+	genes = append(genes, current)
+
+	for _, lines := range genes {
+		var gene uniprot.Gene
+		for _, item := range strings.Split(join(lines), ";") {
+			key, values, ok := strings.Cut(strings.TrimSpace(item), "=")
+			nameType := geneNameTypes[key]
+			if !ok || nameType == "" {
+				continue
+			}
+			for _, v := range splitList(stripEvidence(values), ",") {
+				gene.Name = append(gene.Name, uniprot.GeneName{Type: nameType, Value: v})
+			}
+		}
+		if len(gene.Name) > 0 {
+			e.Gene = append(e.Gene, gene)
+		}
+	}
+}
+
+// organismNames parses an organism description such as
+// "Homo sapiens (Human)." or "Escherichia coli (strain K12).". A
+// parenthesized part starting with a lowercase letter, such as a strain,
+// belongs to the scientific name; the first other one is the common name
+// and any further ones are synonyms.
+func organismNames(s string) []uniprot.OrganismName {
+	s = strings.TrimSuffix(stripEvidence(s), ".")
+	var groups []string
+	for strings.HasSuffix(s, ")") {
+		depth, open := 0, -1
+		for i := len(s) - 1; i >= 0 && open < 0; i-- {
+			switch s[i] {
+			case ')':
+				depth++
+			case '(':
+				if depth--; depth == 0 {
+					open = i
+				}
+			}
+		}
+		if open <= 0 {
+			break
+		}
+		groups = append([]string{s[open+1 : len(s)-1]}, groups...)
+		s = strings.TrimSpace(s[:open])
+	}
+
+	scientific := s
+	var others []uniprot.OrganismName
+	for _, g := range groups {
+		first, _ := utf8.DecodeRuneInString(g)
+		switch {
+		case len(others) == 0 && unicode.IsLower(first):
+			scientific += " (" + g + ")"
+		case len(others) == 0:
+			others = append(others, uniprot.OrganismName{Type: "common", Value: g})
+		default:
+			others = append(others, uniprot.OrganismName{Type: "synonym", Value: g})
+		}
+	}
+	return append([]uniprot.OrganismName{{Type: "scientific", Value: scientific}}, others...)
+}
+
+// taxonomyRefs reads taxonomy cross-references such as "NCBI_TaxID=9606;".
+func taxonomyRefs(s string) []uniprot.DbReference {
+	var refs []uniprot.DbReference
+	for _, item := range splitList(s, ";") {
+		if key, id, ok := strings.Cut(item, "="); ok && key == "NCBI_TaxID" {
+			refs = append(refs, uniprot.DbReference{Type: "NCBI Taxonomy", ID: id})
+		}
+	}
+	return refs
+}
+
+// organismHost reads an OH line such as
+// "NCBI_TaxID=9606; Homo sapiens (Human).".
+func organismHost(text string) uniprot.OrganismHost {
+	ref, names, _ := strings.Cut(strings.TrimSpace(text), ";")
+	return uniprot.OrganismHost{
+		Name:        organismNames(names),
+		DbReference: taxonomyRefs(ref),
+	}
+}
+
+// geneLocationTypes are the gene location types of the XML, which the OG
+// line spells capitalized.
+var geneLocationTypes = []string{
+	"apicoplast", "chloroplast", "organellar chromatophore", "cyanelle",
+	"hydrogenosome", "mitochondrion", "non-photosynthetic plastid",
+	"nucleomorph", "plasmid", "plastid",
+}
+
+// parseOG reads gene locations such as "Plasmid pWR100." or
+// "Mitochondrion.".
+func parseOG(e *uniprot.Entry, s string) {
+	s = strings.TrimSuffix(stripEvidence(s), ".")
+	s = strings.ReplaceAll(s, ", and ", ", ")
+	s = strings.ReplaceAll(s, " and ", ", ")
+	for _, item := range splitList(s, ",") {
+		var loc uniprot.GeneLocation
+		lower := strings.ToLower(item)
+		for _, t := range geneLocationTypes {
+			if strings.HasPrefix(lower, t) {
+				loc.Type = t
+				loc.Name.Value = strings.TrimSpace(item[len(t):])
+				break
+			}
+		}
+		if loc.Type != "" {
+			e.GeneLocation = append(e.GeneLocation, loc)
+		}
+	}
+}
+
+// parseReference reads the lines of a reference. An RN line opens a new
+// reference; the other codes fill in the last one.
+func parseReference(e *uniprot.Entry, code string, texts []string) error {
+	if code == "RN" {
+		key := strings.Trim(stripEvidence(texts[0]), "[]")
+		e.Reference = append(e.Reference, uniprot.Reference{Key: key})
+		return nil
+	}
+	if len(e.Reference) == 0 {
+		return fmt.Errorf("reference line before RN")
+	}
+	ref := &e.Reference[len(e.Reference)-1]
+	text := join(texts)
+	switch code {
+	case "RP":
+		ref.Scope = append(ref.Scope, strings.TrimSuffix(text, "."))
+	case "RX":
+		for _, item := range splitList(text, ";") {
+			if db, id, ok := strings.Cut(item, "="); ok {
+				ref.Citation.DbReference = append(ref.Citation.DbReference, uniprot.DbReference{Type: db, ID: id})
+			}
+		}
+	case "RA":
+		for _, name := range splitList(strings.TrimSuffix(text, ";"), ",") {
+			ref.Citation.AuthorList.Person = append(ref.Citation.AuthorList.Person, uniprot.Person{Name: name})
+		}
+	case "RT":
+		ref.Citation.Title = strings.Trim(strings.TrimSuffix(text, ";"), `"`)
+	case "RL":
+		parseRL(&ref.Citation, text)
+	}
+	return nil
+}
+
+// parseRL fills in the citation from an RL line such as
+// "J. Biol. Chem. 254:1-5(1979).", "Submitted (JAN-2000) to the
+// EMBL/GenBank/DDBJ databases.", "Thesis (1990), Univ. of X, Germany.",
+// "Patent number WO9010703, 20-SEP-1990." or "(In) Doe J. (eds.); Title,
+// pp.11-20, Academic Press, New York (1983).". The line is also kept whole
+// in the Journal text.
+func parseRL(c *uniprot.Citation, rl string) {
+	c.Journal.Value = rl
+	rl = strings.TrimSuffix(rl, ".")
+	switch {
+	case strings.HasPrefix(rl, "Submitted ("):
+		c.Type = "submission"
+		date, rest, _ := strings.Cut(rl[len("Submitted ("):], ")")
+		if t, err := time.Parse("Jan-2006", date); err == nil {
+			date = t.Format("2006-01")
+		}
+		c.Date = date
+		c.DB = strings.TrimPrefix(strings.TrimSpace(rest), "to the ")
+	case strings.HasPrefix(rl, "Thesis"):
+		c.Type = "thesis"
+		fields := strings.Split(rl, ", ")
+		c.Date = year(fields[0])
+		if rest := fields[1:]; len(rest) > 1 {
+			c.Institute, c.Country = strings.Join(rest[:len(rest)-1], ", "), rest[len(rest)-1]
+		} else if len(rest) == 1 {
+			c.Institute = rest[0]
+		}
+	case strings.HasPrefix(rl, "Patent number"):
+		c.Type = "patent"
+		number, date, _ := strings.Cut(strings.TrimSpace(rl[len("Patent number"):]), ", ")
+		c.Number = number
+		if d, err := flatDate(date); err == nil {
+			c.Date = d
+		}
+	case strings.HasPrefix(rl, "Unpublished"):
+		c.Type = "unpublished observations"
+		c.Date = year(rl)
+	case strings.HasPrefix(rl, "(In)"):
+		c.Type = "book"
+		c.Date = year(rl)
+		parseBook(c, strings.TrimSpace(rl[len("(In)"):]))
+	case strings.HasPrefix(rl, "(er)"):
+		c.Type = "online journal article"
+		parseJournal(c, strings.TrimSpace(rl[len("(er)"):]))
+	default:
+		c.Type = "journal article"
+		parseJournal(c, rl)
+	}
+}
+
+// year returns the four-digit year in the last parentheses of s, or "".
+func year(s string) string {
+	if i := strings.LastIndex(s, "("); i >= 0 && len(s) >= i+5 {
+		if _, err := strconv.Atoi(s[i+1 : i+5]); err == nil {
+			return s[i+1 : i+5]
+		}
+	}
+	return ""
+}
+
+// parseJournal reads a journal location such as
+// "J. Mol. Biol. 168:321-331(1983)" into name, volume, pages and year.
+func parseJournal(c *uniprot.Citation, s string) {
+	c.Date = year(s)
+	if i := strings.LastIndex(s, "("); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	i := strings.LastIndexByte(s, ' ')
+	if i < 0 {
+		c.Name = s
+		return
+	}
+	c.Name = s[:i]
+	volume, pages, _ := strings.Cut(s[i+1:], ":")
+	c.Volume = volume
+	c.FirstPage, c.LastPage, _ = strings.Cut(pages, "-")
+}
+
+// parseBook reads the part of a book citation after "(In)", such as
+// "Doe J., Roe R. (eds.); The enzymes, pp.11-20, Academic Press, New York
+// (1983)", into editors, book title, pages, publisher and city.
+func parseBook(c *uniprot.Citation, s string) {
+	if editors, rest, ok := strings.Cut(s, "(eds.);"); ok {
+		for _, name := range splitList(editors, ",") {
+			c.EditorList.Person = append(c.EditorList.Person, uniprot.Person{Name: name})
+		}
+		s = rest
+	}
+	if i := strings.LastIndex(s, "("); i >= 0 {
+		s = s[:i]
+	}
+	fields := splitList(s, ",")
+	var rest []string
+	for i, f := range fields {
+		if pages, ok := strings.CutPrefix(f, "pp."); ok {
+			c.FirstPage, c.LastPage, _ = strings.Cut(pages, "-")
+			rest = fields[i+1:]
+			break
+		}
+		if c.Name != "" {
+			c.Name += ", "
+		}
+		c.Name += f
+	}
+	if len(rest) > 0 {
+		c.Publisher = rest[0]
+	}
+	if len(rest) > 1 {
+		c.City = strings.Join(rest[1:], ", ")
+	}
+}
+
+// commentTypes maps the CC topics whose XML type is not simply the
+// lowercased topic.
+var commentTypes = map[string]string{
+	"PTM":          "PTM",
+	"RNA EDITING":  "RNA editing",
+	"WEB RESOURCE": "online information",
+}
+
+// parseCC reads the comments, each starting with "-!- TOPIC:", up to the
+// copyright notice. Only their text and the molecule they apply to are
+// kept.
+func parseCC(e *uniprot.Entry, texts []string) {
+	var comment *uniprot.Comment
+	var text []string
+	flush := func() {
+		if comment != nil {
+			comment.Text = []uniprot.Text{{Value: stripEvidence(strings.Join(text, " "))}}
+		}
+	}
+	for _, t := range texts {
+		t = strings.TrimSpace(t)
+		if strings.HasPrefix(t, "-----") {
+			break
+		}
+		topic, ok := strings.CutPrefix(t, "-!- ")
+		if !ok {
+			text = append(text, t)
+			continue
+		}
+		flush()
+		topic, rest, _ := strings.Cut(topic, ":")
+		commentType, ok := commentTypes[topic]
+		if !ok {
+			commentType = strings.ToLower(topic)
+		}
+		e.Comment = append(e.Comment, uniprot.Comment{Type: commentType})
+		comment = &e.Comment[len(e.Comment)-1]
+		rest = strings.TrimSpace(rest)
+		// A comment on a component or isoform starts with its name, as in
+		// "[Hemopressin]: Hemopressin acts as ...".
+		if molecule, after, ok := strings.Cut(rest, "]: "); ok && strings.HasPrefix(molecule, "[") {
+			comment.Molecule.Value, rest = molecule[1:], after
+		}
+		text = []string{rest}
+	}
+	flush()
+}
+
+// familyProperties names the fields of the DR lines of the family and
+// domain databases.
+var familyProperties = []string{"entry name", "match status"}
+
+// drProperties names the fields following the ID in the DR lines of the
+// common databases, as the properties of the XML cross-references.
+var drProperties = map[string][]string{
+	"EMBL":     {"protein sequence ID", "status", "molecule type"},
+	"RefSeq":   {"nucleotide sequence ID"},
+	"PDB":      {"method", "resolution", "chains"},
+	"GO":       {"term", "evidence"},
+	"InterPro": {"entry name"},
+	"CDD":      familyProperties,
+	"Gene3D":   familyProperties,
+	"HAMAP":    familyProperties,
+	"NCBIfam":  familyProperties,
+	"PANTHER":  familyProperties,
+	"Pfam":     familyProperties,
+	"PIRSF":    familyProperties,
+	"PRINTS":   familyProperties,
+	"PROSITE":  familyProperties,
+	"SFLD":     familyProperties,
+	"SMART":    familyProperties,
+	"SUPFAM":   familyProperties,
+}
+
+// parseDR reads a cross-reference such as
+// "Ensembl; ENST00000251595.11; ENSP00000251595.6; ENSG00000206172.8. [P69905-1]".
+// The isoform in brackets becomes the molecule of the cross-reference.
+// Fields of databases without known property names, and "-" placeholders,
+// are dropped.
+func parseDR(text string) uniprot.DbReference {
+	text = strings.TrimSpace(text)
+	molecule := ""
+	if i := strings.LastIndex(text, " ["); i >= 0 && strings.HasSuffix(text, "]") {
+		text, molecule = text[:i], text[i+2:len(text)-1]
+	}
+	fields := strings.Split(strings.TrimSuffix(text, "."), "; ")
+	ref := uniprot.DbReference{Type: fields[0]}
+	ref.Molecule.ID = molecule
+	if len(fields) > 1 {
+		ref.ID = fields[1]
+	}
+	names := drProperties[ref.Type]
+	if strings.HasPrefix(ref.Type, "Ensembl") {
+		names = []string{"protein sequence ID", "gene ID"}
+	}
+	for i, value := range fields[min(2, len(fields)):] {
+		if i < len(names) && value != "-" {
+			ref.Property = append(ref.Property, uniprot.Property{Type: names[i], Value: value})
+		}
+	}
+	return ref
+}
+
+// parseSQ reads the sequence header, e.g.
+// "SEQUENCE   142 AA;  15258 MW;  15E13666573BBBAE CRC64;".
+func parseSQ(e *uniprot.Entry, text string) error {
+	fields := strings.Fields(text)
+	if len(fields) < 6 || fields[0] != "SEQUENCE" {
+		return fmt.Errorf("malformed sequence header %q", text)
+	}
+	var err error
+	if e.Sequence.Length, err = strconv.Atoi(fields[1]); err != nil {
+		return err
+	}
+	if e.Sequence.Mass, err = strconv.Atoi(fields[3]); err != nil {
+		return err
+	}
+	e.Sequence.Checksum = fields[5]
+	return nil
+}
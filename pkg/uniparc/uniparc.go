@@ -0,0 +1,70 @@
+package uniparc
+
+import (
+	"encoding/xml"
+	"iter"
+
+	"github.com/arkinjo/TogoProt2/pkg/uniprot"
+)
+
+// Entry is a UniParc entry: a unique sequence and its cross-references to
+// the source databases it appears in.
+type Entry struct {
+	XMLName                xml.Name                 `xml:"entry"`
+	Dataset                string                   `xml:"dataset,attr"`
+	UUID                   string                   `xml:"UUID,attr"`
+	Accession              string                   `xml:"accession"`
+	DbReference            []DbReference            `xml:"dbReference"`
+	SignatureSequenceMatch []SignatureSequenceMatch `xml:"signatureSequenceMatch"`
+	Sequence               uniprot.Sequence         `xml:"sequence"`
+}
+
+type DbReference struct {
+	XMLName  xml.Name   `xml:"dbReference"`
+	Type     string     `xml:"type,attr"`
+	ID       string     `xml:"id,attr"`
+	VersionI int        `xml:"version_i,attr"`
+	Active   string     `xml:"active,attr"`
+	Version  int        `xml:"version,attr"`
+	Created  string     `xml:"created,attr"`
+	Last     string     `xml:"last,attr"`
+	Property []Property `xml:"property"`
+}
+
+type Property struct {
+	XMLName xml.Name `xml:"property"`
+	Type    string   `xml:"type,attr"`
+	Value   string   `xml:"value,attr"`
+}
+
+type SignatureSequenceMatch struct {
+	XMLName  xml.Name   `xml:"signatureSequenceMatch"`
+	Database string     `xml:"database,attr"`
+	ID       string     `xml:"id,attr"`
+	InterPro InterPro   `xml:"ipr"`
+	Location []Location `xml:"lcn"`
+}
+
+type InterPro struct {
+	XMLName xml.Name `xml:"ipr"`
+	ID      string   `xml:"id,attr"`
+	Name    string   `xml:"name,attr"`
+}
+
+type Location struct {
+	XMLName xml.Name `xml:"lcn"`
+	Start   int      `xml:"start,attr"`
+	End     int      `xml:"end,attr"`
+}
+
+// IsActive reports whether the cross-reference is still active in its
+// source database.
+func (r DbReference) IsActive() bool {
+	return r.Active == "Y"
+}
+
+// UniParcEntries returns an iterator over UniParc entries from an XML file,
+// optionally gzipped.
+func UniParcEntries(filePath string) iter.Seq2[Entry, error] {
+	return uniprot.Elements[Entry](filePath, "entry")
+}
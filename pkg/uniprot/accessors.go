@@ -0,0 +1,243 @@
+package uniprot
+
+import "strconv"
+
+// appendUnique appends the values not yet in seen to list.
+func appendUnique(list []string, seen map[string]bool, values ...string) []string {
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// PrimaryAccession returns the primary (first) accession of the entry, or
+// "" if it has none.
+func (e Entry) PrimaryAccession() string {
+	if len(e.Accession) == 0 {
+		return ""
+	}
+	return e.Accession[0]
+}
+
+// citationIDs collects the IDs of the given database type cited by the
+// references and the evidence sources of the entry, without duplicates.
+func (e Entry) citationIDs(dbType string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, ref := range e.Reference {
+		for _, db := range ref.Citation.DbReference {
+			if db.Type == dbType {
+				ids = appendUnique(ids, seen, db.ID)
+			}
+		}
+	}
+	for _, ev := range e.Evidence {
+		for _, db := range ev.Source.DbReference {
+			if db.Type == dbType {
+				ids = appendUnique(ids, seen, db.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// PubMedIDs returns the PubMed IDs cited by the entry's references and
+// evidence sources, in order of first appearance.
+func (e Entry) PubMedIDs() []string {
+	return e.citationIDs("PubMed")
+}
+
+// DOIs returns the DOIs cited by the entry's references and evidence
+// sources, in order of first appearance.
+func (e Entry) DOIs() []string {
+	return e.citationIDs("DOI")
+}
+
+// CommentTypes returns the distinct comment types of the entry in order of
+// first appearance.
+func (e Entry) CommentTypes() []string {
+	var types []string
+	seen := make(map[string]bool)
+	for _, c := range e.Comment {
+		types = appendUnique(types, seen, c.Type)
+	}
+	return types
+}
+
+// CommentText returns the text blocks of the comments of the given type,
+// such as "function", "induction" or "pathway".
+func (e Entry) CommentText(commentType string) []string {
+	var texts []string
+	for _, c := range e.Comment {
+		if c.Type != commentType {
+			continue
+		}
+		for _, t := range c.Text {
+			texts = append(texts, t.Value)
+		}
+	}
+	return texts
+}
+
+// GeneLocations returns the types of the gene locations of the entry, such
+// as "mitochondrion", "chloroplast" or "plasmid".
+func (e Entry) GeneLocations() []string {
+	var types []string
+	for _, loc := range e.GeneLocation {
+		types = append(types, loc.Type)
+	}
+	return types
+}
+
+// Domain returns the top-level taxon of the organism's lineage, i.e.
+// "Bacteria", "Archaea", "Eukaryota" or "Viruses", or "" if the lineage is
+// missing.
+func (e Entry) Domain() string {
+	if len(e.Organism.Lineage.Taxon) == 0 {
+		return ""
+	}
+	return e.Organism.Lineage.Taxon[0].Value
+}
+
+// EntryName returns the UniProtKB entry name (mnemonic) such as "HBA_HUMAN".
+func (e Entry) EntryName() string {
+	if len(e.Name) == 0 {
+		return ""
+	}
+	return e.Name[0].Value
+}
+
+// IsReviewed reports whether the entry belongs to Swiss-Prot.
+func (e Entry) IsReviewed() bool {
+	return e.Dataset == "Swiss-Prot"
+}
+
+// ProteinName returns the recommended full name of the protein, falling
+// back to the first submitted name for unreviewed entries.
+func (e Entry) ProteinName() string {
+	if name := e.Protein.RecommendedName.FullName.Value; name != "" {
+		return name
+	}
+	if len(e.Protein.SubmittedName) > 0 {
+		return e.Protein.SubmittedName[0].FullName.Value
+	}
+	return ""
+}
+
+// GeneName returns the primary gene name of the entry, or "" if none.
+func (e Entry) GeneName() string {
+	for _, gene := range e.Gene {
+		for _, name := range gene.Name {
+			if name.Type == "primary" {
+				return name.Value
+			}
+		}
+	}
+	return ""
+}
+
+// geneNames returns the gene names of the given type, such as "primary",
+// "synonym", "ordered locus" or "ORF".
+func (e Entry) geneNames(nameType string) []string {
+	var names []string
+	for _, gene := range e.Gene {
+		for _, name := range gene.Name {
+			if name.Type == nameType {
+				names = append(names, name.Value)
+			}
+		}
+	}
+	return names
+}
+
+// OrderedLocusNames returns the ordered locus names of the entry's genes,
+// such as "b0001".
+func (e Entry) OrderedLocusNames() []string {
+	return e.geneNames("ordered locus")
+}
+
+// ORFNames returns the ORF names of the entry's genes.
+func (e Entry) ORFNames() []string {
+	return e.geneNames("ORF")
+}
+
+// ScientificName returns the scientific name of the source organism.
+func (e Entry) ScientificName() string {
+	for _, name := range e.Organism.Name {
+		if name.Type == "scientific" {
+			return name.Value
+		}
+	}
+	return ""
+}
+
+// CommonName returns the common name of the source organism, or "" if none.
+func (e Entry) CommonName() string {
+	for _, name := range e.Organism.Name {
+		if name.Type == "common" {
+			return name.Value
+		}
+	}
+	return ""
+}
+
+// OrganismNames returns every name of the source organism grouped by type,
+// such as "scientific", "common" and "synonym", in document order.
+func (e Entry) OrganismNames() map[string][]string {
+	names := make(map[string][]string)
+	for _, name := range e.Organism.Name {
+		names[name.Type] = append(names[name.Type], name.Value)
+	}
+	return names
+}
+
+// TaxID returns the NCBI taxonomy identifier of the source organism, or 0
+// if it is missing.
+func (e Entry) TaxID() int {
+	for _, ref := range e.Organism.DbReference {
+		if ref.Type == "NCBI Taxonomy" {
+			id, _ := strconv.Atoi(ref.ID)
+			return id
+		}
+	}
+	return 0
+}
+
+// Keywords returns the keyword values of the entry.
+func (e Entry) Keywords() []string {
+	var keywords []string
+	for _, k := range e.Keyword {
+		keywords = append(keywords, k.Value)
+	}
+	return keywords
+}
+
+// ECNumbers returns the EC numbers given in the protein names, without
+// duplicates.
+func (e Entry) ECNumbers() []string {
+	var ecs []string
+	seen := make(map[string]bool)
+	ecs = appendUnique(ecs, seen, e.Protein.RecommendedName.EcNumber...)
+	for _, name := range e.Protein.AlternativeName {
+		ecs = appendUnique(ecs, seen, name.EcNumber...)
+	}
+	for _, name := range e.Protein.SubmittedName {
+		ecs = appendUnique(ecs, seen, name.EcNumber...)
+	}
+	return ecs
+}
+
+// DbReferenceIDs returns the IDs of the entry's cross-references to the
+// given database, such as "GO" or "PDB".
+func (e Entry) DbReferenceIDs(dbType string) []string {
+	var ids []string
+	for _, ref := range e.DbReference {
+		if ref.Type == dbType {
+			ids = append(ids, ref.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,192 @@
+package uniprot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptySequence is returned when aligning an empty sequence.
+var ErrEmptySequence = errors.New("uniprot: empty sequence")
+
+// Gap penalties used by SequenceIdentity. A gap of length k costs
+// gapOpen + (k-1)*gapExtend.
+const (
+	gapOpen   = 10
+	gapExtend = 1
+)
+
+const blosum62Text = `
+   A  R  N  D  C  Q  E  G  H  I  L  K  M  F  P  S  T  W  Y  V  B  Z  X  *
+A  4 -1 -2 -2  0 -1 -1  0 -2 -1 -1 -1 -1 -2 -1  1  0 -3 -2  0 -2 -1  0 -4
+R -1  5  0 -2 -3  1  0 -2  0 -3 -2  2 -1 -3 -2 -1 -1 -3 -2 -3 -1  0 -1 -4
+N -2  0  6  1 -3  0  0  0  1 -3 -3  0 -2 -3 -2  1  0 -4 -2 -3  3  0 -1 -4
+D -2 -2  1  6 -3  0  2 -1 -1 -3 -4 -1 -3 -3 -1  0 -1 -4 -3 -3  4  1 -1 -4
+C  0 -3 -3 -3  9 -3 -4 -3 -3 -1 -1 -3 -1 -2 -3 -1 -1 -2 -2 -1 -3 -3 -2 -4
+Q -1  1  0  0 -3  5  2 -2  0 -3 -2  1  0 -3 -1  0 -1 -2 -1 -2  0  3 -1 -4
+E -1  0  0  2 -4  2  5 -2  0 -3 -3  1 -2 -3 -1  0 -1 -3 -2 -2  1  4 -1 -4
+G  0 -2  0 -1 -3 -2 -2  6 -2 -4 -4 -2 -3 -3 -2  0 -2 -2 -3 -3 -1 -2 -1 -4
+H -2  0  1 -1 -3  0  0 -2  8 -3 -3 -1 -2 -1 -2 -1 -2 -2  2 -3  0  0 -1 -4
+I -1 -3 -3 -3 -1 -3 -3 -4 -3  4  2 -3  1  0 -3 -2 -1 -3 -1  3 -3 -3 -1 -4
+L -1 -2 -3 -4 -1 -2 -3 -4 -3  2  4 -2  2  0 -3 -2 -1 -2 -1  1 -4 -3 -1 -4
+K -1  2  0 -1 -3  1  1 -2 -1 -3 -2  5 -1 -3 -1  0 -1 -3 -2 -2  0  1 -1 -4
+M -1 -1 -2 -3 -1  0 -2 -3 -2  1  2 -1  5  0 -2 -1 -1 -1 -1  1 -3 -1 -1 -4
+F -2 -3 -3 -3 -2 -3 -3 -3 -1  0  0 -3  0  6 -4 -2 -2  1  3 -1 -3 -3 -1 -4
+P -1 -2 -2 -1 -3 -1 -1 -2 -2 -3 -3 -1 -2 -4  7 -1 -1 -4 -3 -2 -2 -1 -2 -4
+S  1 -1  1  0 -1  0  0  0 -1 -2 -2  0 -1 -2 -1  4  1 -3 -2 -2  0  0  0 -4
+T  0 -1  0 -1 -1 -1 -1 -2 -2 -1 -1 -1 -1 -2 -1  1  5 -2 -2  0 -1 -1  0 -4
+W -3 -3 -4 -4 -2 -2 -3 -2 -2 -3 -2 -3 -1  1 -4 -3 -2 11  2 -3 -4 -3 -2 -4
+Y -2 -2 -2 -3 -2 -1 -2 -3  2 -1 -1 -2 -1  3 -3 -2 -2  2  7 -1 -3 -2 -1 -4
+V  0 -3 -3 -3 -1 -2 -2 -3 -3  3  1 -2  1 -1 -2 -2  0 -3 -1  4 -3 -2 -1 -4
+B -2 -1  3  4 -3  0  1 -1  0 -3 -4  0 -3 -3 -2  0 -1 -4 -3 -3  4  1 -1 -4
+Z -1  0  0  1 -3  3  4 -2  0 -3 -3  1 -1 -3 -1  0 -1 -3 -2 -2  1  4 -1 -4
+X  0 -1 -1 -1 -2 -1 -1 -1 -1 -1 -1 -1 -1 -1 -2  0  0 -2 -1 -1 -1 -1 -1 -4
+* -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4 -4  1
+`
+
+// blosum62 holds the BLOSUM62 scores indexed by residue code, and
+// blosumIndex maps residue letters to codes. Letters missing from the
+// matrix (J, O, U) are scored as X.
+var blosum62, blosumIndex = parseMatrix(blosum62Text)
+
+func parseMatrix(text string) ([][]int, [256]int) {
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	letters := strings.Fields(lines[0])
+	for i, l := range letters {
+		index[l[0]] = i
+		index[strings.ToLower(l)[0]] = i
+	}
+	x := index['X']
+	for c := 'A'; c <= 'Z'; c++ {
+		if index[c] < 0 {
+			index[c] = x
+			index[c+'a'-'A'] = x
+		}
+	}
+
+	matrix := make([][]int, len(letters))
+	for i, line := range lines[1:] {
+		for _, f := range strings.Fields(line)[1:] {
+			var score int
+			fmt.Sscan(f, &score)
+			matrix[i] = append(matrix[i], score)
+		}
+	}
+	return matrix, index
+}
+
+// encodeResidues maps seq to BLOSUM62 residue codes.
+func encodeResidues(seq string) ([]int, error) {
+	if seq == "" {
+		return nil, ErrEmptySequence
+	}
+	codes := make([]int, len(seq))
+	for i := 0; i < len(seq); i++ {
+		codes[i] = blosumIndex[seq[i]]
+		if codes[i] < 0 {
+			return nil, fmt.Errorf("uniprot: invalid residue %q at position %d", seq[i], i+1)
+		}
+	}
+	return codes, nil
+}
+
+// Alignment states of the affine-gap recurrence: a and b aligned, a
+// aligned to a gap, b aligned to a gap.
+const (
+	stateMatch = iota
+	stateGapB
+	stateGapA
+)
+
+// SequenceIdentity globally aligns the protein sequences a and b with the
+// Needleman-Wunsch algorithm using affine gaps (Gotoh), the BLOSUM62 matrix
+// and gap penalties of 10 to open and 1 to extend, and returns the percent
+// identity: the identical aligned positions over the alignment length,
+// gaps included. Letters are matched case-insensitively. Time and memory
+// are proportional to len(a)*len(b), which suits occasional comparisons,
+// not database searches.
+func SequenceIdentity(a, b string) (float64, error) {
+	x, err := encodeResidues(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := encodeResidues(b)
+	if err != nil {
+		return 0, err
+	}
+
+	const negInf = -1 << 30
+	n, m := len(x), len(y)
+	width := m + 1
+	var score [3][]int
+	var from [3][]uint8
+	for s := range score {
+		score[s] = make([]int, (n+1)*width)
+		from[s] = make([]uint8, (n+1)*width)
+	}
+	// best returns the highest of the candidate scores and its state.
+	best := func(c [3]int) (int, uint8) {
+		s := uint8(stateMatch)
+		if c[stateGapB] > c[s] {
+			s = stateGapB
+		}
+		if c[stateGapA] > c[s] {
+			s = stateGapA
+		}
+		return c[s], s
+	}
+
+	for i := 0; i <= n; i++ {
+		for j := 0; j <= m; j++ {
+			k := i*width + j
+			switch {
+			case i == 0 && j == 0:
+				score[stateGapB][k], score[stateGapA][k] = negInf, negInf
+				continue
+			case i == 0:
+				score[stateMatch][k], score[stateGapB][k] = negInf, negInf
+				score[stateGapA][k] = -gapOpen - (j-1)*gapExtend
+				from[stateGapA][k] = stateGapA
+				continue
+			case j == 0:
+				score[stateMatch][k], score[stateGapA][k] = negInf, negInf
+				score[stateGapB][k] = -gapOpen - (i-1)*gapExtend
+				from[stateGapB][k] = stateGapB
+				continue
+			}
+
+			diag, up, left := k-width-1, k-width, k-1
+			s, st := best([3]int{score[stateMatch][diag], score[stateGapB][diag], score[stateGapA][diag]})
+			score[stateMatch][k], from[stateMatch][k] = s+blosum62[x[i-1]][y[j-1]], st
+			score[stateGapB][k], from[stateGapB][k] = best([3]int{
+				score[stateMatch][up] - gapOpen, score[stateGapB][up] - gapExtend, score[stateGapA][up] - gapOpen})
+			score[stateGapA][k], from[stateGapA][k] = best([3]int{
+				score[stateMatch][left] - gapOpen, score[stateGapB][left] - gapOpen, score[stateGapA][left] - gapExtend})
+		}
+	}
+
+	k := n*width + m
+	_, state := best([3]int{score[stateMatch][k], score[stateGapB][k], score[stateGapA][k]})
+	identical, length := 0, 0
+	for i, j := n, m; i > 0 || j > 0; length++ {
+		k := i*width + j
+		prev := from[state][k]
+		switch state {
+		case stateMatch:
+			if strings.EqualFold(a[i-1:i], b[j-1:j]) {
+				identical++
+			}
+			i, j = i-1, j-1
+		case stateGapB:
+			i--
+		case stateGapA:
+			j--
+		}
+		state = prev
+	}
+	return 100 * float64(identical) / float64(length), nil
+}
@@ -0,0 +1,139 @@
+package uniprot
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// biophysicochemicalComments returns the entry's comments of type
+// "biophysicochemical properties".
+func (e Entry) biophysicochemicalComments() []Comment {
+	var comments []Comment
+	for _, c := range e.Comment {
+		if c.Type == "biophysicochemical properties" {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// OptimalPH returns the pH optimum stated in the pH dependence of the
+// entry's biophysicochemical properties, such as "Optimum pH is 7.0-8.5.",
+// "Optimum pH is between 6 and 7." or "Active from pH 5 to 10, with an
+// optimum at pH 8.". A single value is returned as lo == hi. The first
+// number after the word "optimum", or the first number of the text if it
+// has no such word, is taken, together with a second one joined by "-",
+// "to" or "and". ok is false if no text yields a value between 0 and 14.
+func (e Entry) OptimalPH() (lo, hi float64, ok bool) {
+	for _, c := range e.biophysicochemicalComments() {
+		text := c.Ph.Value
+		if i := strings.Index(strings.ToLower(text), "optimum"); i >= 0 {
+			text = text[i:]
+		}
+		lo, hi, ok = parseRange(text)
+		if ok && lo >= 0 && hi <= 14 {
+			return lo, hi, true
+		}
+	}
+	return 0, 0, false
+}
+
+// parseRange reads the first number of s, followed by an optional second
+// one after "-", "to" or "and", and returns them in increasing order.
+func parseRange(s string) (lo, hi float64, ok bool) {
+	lo, rest, ok := nextNumber(s)
+	if !ok {
+		return 0, 0, false
+	}
+	rest = strings.TrimSpace(rest)
+	for _, sep := range []string{"-", "–", "to ", "and "} {
+		if after, found := strings.CutPrefix(rest, sep); found {
+			if n, _, ok := leadingNumber(strings.TrimSpace(after)); ok {
+				return min(lo, n), max(lo, n), true
+			}
+			break
+		}
+	}
+	return lo, lo, true
+}
+
+// nextNumber finds the first decimal number in s and returns it with the
+// text following it.
+func nextNumber(s string) (float64, string, bool) {
+	i := strings.IndexFunc(s, unicode.IsDigit)
+	if i < 0 {
+		return 0, "", false
+	}
+	return leadingNumber(s[i:])
+}
+
+// leadingNumber parses the decimal number at the start of s, such as "7"
+// or "8.25", and returns it with the text following it. A trailing period
+// ending a sentence is not part of the number.
+func leadingNumber(s string) (float64, string, bool) {
+	n := 0
+	for n < len(s) && (s[n] >= '0' && s[n] <= '9' || s[n] == '.' && n+1 < len(s) && s[n+1] >= '0' && s[n+1] <= '9') {
+		n++
+	}
+	v, err := strconv.ParseFloat(s[:n], 64)
+	if n == 0 || err != nil {
+		return 0, s, false
+	}
+	return v, s[n:], true
+}
+
+// KmValue is a Michaelis constant parsed from the kinetics of an entry.
+type KmValue struct {
+	Value float64
+	// Unit is the concentration unit as written, e.g. "mM" or "uM".
+	Unit string
+	// Molar is Value converted to mol/l, or 0 if Unit is not a known
+	// concentration unit.
+	Molar float64
+	// Substrate is the text after "for", such as "ATP".
+	Substrate string
+	// Text is the text of the KM element.
+	Text string
+}
+
+// molarUnits maps concentration units to their factor to mol/l.
+var molarUnits = map[string]float64{
+	"M":  1,
+	"mM": 1e-3,
+	"uM": 1e-6,
+	"µM": 1e-6,
+	"μM": 1e-6,
+	"nM": 1e-9,
+	"pM": 1e-12,
+}
+
+// KineticKm returns the Km values of the entry's biophysicochemical
+// properties. The text of each KM element, such as "8.3 uM for ATP", is
+// split into value, unit and substrate; the unit attribute is used when the
+// text names none. Texts that do not start with a number are skipped.
+func (e Entry) KineticKm() []KmValue {
+	var values []KmValue
+	for _, c := range e.biophysicochemicalComments() {
+		for _, km := range c.KineticParameters.Km {
+			text := strings.TrimSpace(km.Value)
+			v, rest, ok := leadingNumber(text)
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(rest)
+			kv := KmValue{Value: v, Unit: km.Unit, Text: text}
+			if len(fields) > 0 && fields[0] != "for" {
+				kv.Unit, fields = fields[0], fields[1:]
+			}
+			if len(fields) > 0 && fields[0] == "for" {
+				kv.Substrate = strings.Join(fields[1:], " ")
+			}
+			if f, ok := molarUnits[kv.Unit]; ok {
+				kv.Molar = v * f
+			}
+			values = append(values, kv)
+		}
+	}
+	return values
+}
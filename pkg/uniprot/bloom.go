@@ -0,0 +1,161 @@
+package uniprot
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+)
+
+// bloomMagic starts the serialized form of a BloomFilter.
+const bloomMagic = "UPBF\x01"
+
+// maxBloomHashes caps the number of hash functions. It is reached only for
+// false-positive rates below about 1e-19.
+const maxBloomHashes = 64
+
+// ErrBloomFormat is returned by LoadBloomFilter for input that is not a
+// serialized BloomFilter.
+var ErrBloomFormat = errors.New("uniprot: invalid bloom filter data")
+
+// BloomFilter is a compact, probabilistic set of accessions. Contains never
+// reports a false negative, but reports a false positive for an absent
+// accession with about the probability the filter was built for.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    uint32 // number of hash functions
+}
+
+// NewBloomFilter returns an empty filter sized to hold n accessions with
+// the given false-positive rate, which must be between 0 and 1.
+func NewBloomFilter(n int, falsePositiveRate float64) (*BloomFilter, error) {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("uniprot: invalid false-positive rate %g", falsePositiveRate)
+	}
+	n = max(n, 1)
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	m = max(m, 64)
+	k := uint32(min(maxBloomHashes, max(1, math.Round(float64(m)/float64(n)*math.Ln2))))
+	return &BloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}, nil
+}
+
+// bloomHashes returns the two base hashes of acc, combined by double hashing
+// into the k bit indexes.
+func bloomHashes(acc string) (h1, h2 uint64) {
+	a, b := fnv.New64a(), fnv.New64()
+	io.WriteString(a, acc)
+	io.WriteString(b, acc)
+	return a.Sum64(), b.Sum64() | 1
+}
+
+// Add inserts acc into the filter.
+func (b *BloomFilter) Add(acc string) {
+	h1, h2 := bloomHashes(acc)
+	for i := range uint64(b.k) {
+		bit := (h1 + i*h2) % b.m
+		b.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Contains reports whether acc may be in the filter. A false result is
+// certain; a true result is wrong with the filter's false-positive rate.
+// Accessions are compared exactly, so normalize them with
+// NormalizeAccession first if the input may be untidy.
+func (b *BloomFilter) Contains(acc string) bool {
+	h1, h2 := bloomHashes(acc)
+	for i := range uint64(b.k) {
+		bit := (h1 + i*h2) % b.m
+		if b.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Save writes the filter to w in a binary form read by LoadBloomFilter.
+func (b *BloomFilter) Save(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(bloomMagic)
+	binary.Write(bw, binary.LittleEndian, b.m)
+	binary.Write(bw, binary.LittleEndian, b.k)
+	if err := binary.Write(bw, binary.LittleEndian, b.bits); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// LoadBloomFilter reads a filter written by Save. Headers with no bits or
+// hash functions, or more than 64 hash functions, are rejected with
+// ErrBloomFormat, and so is input shorter than its header announces; the
+// bit array is allocated only as the data arrives, so a corrupt header
+// cannot cause a huge allocation.
+func LoadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(bloomMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != bloomMagic {
+		return nil, ErrBloomFormat
+	}
+	var b BloomFilter
+	if err := binary.Read(br, binary.LittleEndian, &b.m); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(br, binary.LittleEndian, &b.k); err != nil {
+		return nil, err
+	}
+	if b.m == 0 || b.k == 0 || b.k > maxBloomHashes || b.m > 1<<40 {
+		return nil, ErrBloomFormat
+	}
+	words := (b.m + 63) / 64
+	data, err := io.ReadAll(io.LimitReader(br, int64(words*8)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != words*8 {
+		return nil, ErrBloomFormat
+	}
+	b.bits = make([]uint64, words)
+	for i := range b.bits {
+		b.bits[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	return &b, nil
+}
+
+// BuildAccessionBloom returns a filter holding every accession, primary and
+// secondary, of an XML file, optionally gzipped, sized for expected
+// accessions at the given false-positive rate. Only the accessions are
+// decoded. The filter takes about 1.44*log2(1/rate) bits per accession,
+// some 1.2 MB per million accessions at a 1% rate.
+//
+// If expected is 0 or less, the file is read twice: once to count the
+// accessions and once to add them. On a full UniProtKB dump this doubles
+// the cost, so pass a count, e.g. from the release notes, when one is
+// known. A filter holding more accessions than expected still works, with
+// a higher false-positive rate.
+func BuildAccessionBloom(filePath string, expected int, falsePositiveRate float64) (*BloomFilter, error) {
+	if expected <= 0 {
+		expected = 0
+		for entry, err := range UniProtEntriesFields(filePath, FieldAccession) {
+			if err != nil {
+				return nil, err
+			}
+			expected += len(entry.Accession)
+		}
+	}
+	b, err := NewBloomFilter(expected, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+	for entry, err := range UniProtEntriesFields(filePath, FieldAccession) {
+		if err != nil {
+			return nil, err
+		}
+		for _, acc := range entry.Accession {
+			b.Add(acc)
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,59 @@
+package uniprot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestBloomFilterSaveLoad(t *testing.T) {
+	for _, expected := range []int{0, 10} {
+		b, err := BuildAccessionBloom(sampleFile, expected, 0.01)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := b.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadBloomFilter(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, acc := range []string{"P69905", "Q00001"} {
+			if !loaded.Contains(acc) {
+				t.Errorf("expected %d: loaded filter lacks %s", expected, acc)
+			}
+		}
+	}
+}
+
+// bloomHeader returns a serialized filter header announcing m bits and k
+// hash functions.
+func bloomHeader(m uint64, k uint32) []byte {
+	buf := []byte(bloomMagic)
+	buf = binary.LittleEndian.AppendUint64(buf, m)
+	return binary.LittleEndian.AppendUint32(buf, k)
+}
+
+func TestLoadBloomFilterRejectsBadHeaders(t *testing.T) {
+	tests := map[string][]byte{
+		"no magic":        []byte("UPBX"),
+		"no bits":         bloomHeader(0, 3),
+		"no hashes":       bloomHeader(64, 0),
+		"too many hashes": append(bloomHeader(64, 1<<30), make([]byte, 8)...),
+		// 2^40 bits would take 128 GiB; the 8 bytes present must not
+		// cause that allocation.
+		"short payload": append(bloomHeader(1<<40, 3), make([]byte, 8)...),
+		"too many bits": bloomHeader(1<<41, 3),
+	}
+	for name, data := range tests {
+		if _, err := LoadBloomFilter(bytes.NewReader(data)); !errors.Is(err, ErrBloomFormat) {
+			t.Errorf("%s: err = %v, want ErrBloomFormat", name, err)
+		}
+	}
+	if n := allocated(func() { LoadBloomFilter(bytes.NewReader(tests["short payload"])) }); n > 1<<20 {
+		t.Errorf("loading a truncated filter allocated %d bytes", n)
+	}
+}
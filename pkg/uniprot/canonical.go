@@ -0,0 +1,224 @@
+package uniprot
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Canonical renders the entry in a normalized text form for comparisons in
+// tests. Fields appear in a fixed order, one per line. Collections whose
+// order carries no meaning (keywords, cross-references and their
+// properties, features, comments, references, evidence, organism names)
+// are sorted, so entries that differ only in the order of such elements
+// render identically. Accessions, entry and protein names, gene names and
+// the lineage keep their order, which is significant. Every field decoded
+// into the entry is rendered, so entries with the same rendering are equal
+// up to the order of unordered collections.
+func (e Entry) Canonical() string {
+	var sb strings.Builder
+	field := func(name string, value any) {
+		fmt.Fprintf(&sb, "%s: %v\n", name, value)
+	}
+	block := func(name string, items []string) {
+		slices.Sort(items)
+		for _, item := range items {
+			fmt.Fprintf(&sb, "%s: %s\n", name, item)
+		}
+	}
+
+	field("dataset", quote(e.Dataset))
+	field("created", quote(e.Created))
+	field("modified", quote(e.Modified))
+	field("version", e.Version)
+	field("accession", quoteAll(e.Accession))
+	for _, n := range e.Name {
+		field("name", n.Type+"="+quote(n.Value))
+	}
+	field("recommendedName", canonicalName(e.Protein.RecommendedName.FullName, e.Protein.RecommendedName.ShortName, e.Protein.RecommendedName.EcNumber))
+	for _, n := range e.Protein.AlternativeName {
+		field("alternativeName", canonicalName(n.FullName, n.ShortName, n.EcNumber))
+	}
+	for _, n := range e.Protein.SubmittedName {
+		field("submittedName", canonicalName(n.FullName, n.ShortName, n.EcNumber))
+	}
+	for _, g := range e.Gene {
+		field("gene", canonicalGeneNames(g.Name))
+	}
+	field("organism", canonicalOrganism(e.Organism.Name, e.Organism.DbReference, e.Organism.Lineage))
+	block("organismHost", mapSlice(e.OrganismHost, func(h OrganismHost) string {
+		return canonicalOrganism(h.Name, h.DbReference, h.Lineage)
+	}))
+	block("geneLocation", mapSlice(e.GeneLocation, func(g GeneLocation) string {
+		return fmt.Sprintf("type=%s gene=%s name=%s:%s chromosome=%s mapPosition=%s evidence=[%s]",
+			quote(g.Type), quote(g.Gene), g.Name.Type, quote(g.Name.Value),
+			quote(g.Chromosome), quote(g.MapPosition), canonicalEvidence(g.EvidenceKeys, g.Evidence))
+	}))
+	block("reference", mapSlice(e.Reference, canonicalReference))
+	block("comment", mapSlice(e.Comment, canonicalComment))
+	block("dbReference", mapSlice(e.DbReference, canonicalDbReference))
+	field("proteinExistence", quote(e.ProteinExistence.Type))
+	block("keyword", mapSlice(e.Keyword, func(k Keyword) string {
+		return fmt.Sprintf("%s %s evidence=[%s]", k.ID, quote(k.Value), canonicalEvidence(k.EvidenceKeys, k.Evidence))
+	}))
+	block("feature", mapSlice(e.Feature, canonicalFeature))
+	block("evidence", mapSlice(e.Evidence, canonicalEvidenceElement))
+	field("sequence", fmt.Sprintf("length=%d mass=%d version=%d modified=%s checksum=%s precursor=%t fragment=%s %s",
+		e.Sequence.Length, e.Sequence.Mass, e.Sequence.Version, e.Sequence.Modified,
+		e.Sequence.Checksum, e.Sequence.Precursor, quote(e.Sequence.Fragment), e.SequenceString()))
+	return sb.String()
+}
+
+func quote(s string) string {
+	return fmt.Sprintf("%q", s)
+}
+
+func quoteAll(values []string) string {
+	return strings.Join(mapSlice(values, quote), " ")
+}
+
+func mapSlice[T any](items []T, f func(T) string) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = f(item)
+	}
+	return out
+}
+
+func sortedMap[T any](items []T, f func(T) string) []string {
+	out := mapSlice(items, f)
+	slices.Sort(out)
+	return out
+}
+
+func canonicalName(full FullName, short []ShortName, ec []string) string {
+	return fmt.Sprintf("full=%s[%s] short=[%s] ec=[%s]", quote(full.Value), canonicalEvidence(full.EvidenceKeys, full.Evidence),
+		strings.Join(mapSlice(short, func(s ShortName) string {
+			return quote(s.Value) + "[" + canonicalEvidence(s.EvidenceKeys, s.Evidence) + "]"
+		}), " "),
+		strings.Join(ec, " "))
+}
+
+func canonicalGeneNames(names []GeneName) string {
+	return strings.Join(mapSlice(names, func(n GeneName) string { return n.Type + "=" + quote(n.Value) }), " ")
+}
+
+func canonicalOrganismNames(names []OrganismName) string {
+	return strings.Join(sortedMap(names, func(n OrganismName) string { return n.Type + "=" + quote(n.Value) }), " ")
+}
+
+func canonicalOrganism(names []OrganismName, refs []DbReference, lineage Lineage) string {
+	return fmt.Sprintf("names=[%s] refs=[%s] lineage=[%s]",
+		canonicalOrganismNames(names),
+		canonicalDbReferences(refs),
+		strings.Join(mapSlice(lineage.Taxon, func(t Taxon) string {
+			return quote(t.Value) + "[" + canonicalEvidence("", t.Evidence) + "]"
+		}), " "))
+}
+
+// canonicalEvidence renders the keys of an evidence attribute followed by
+// nested evidence elements.
+func canonicalEvidence(keys string, evidence []Evidence) string {
+	items := strings.Fields(keys)
+	slices.Sort(items)
+	return strings.Join(append(items, sortedMap(evidence, canonicalEvidenceElement)...), " ")
+}
+
+func canonicalEvidenceElement(ev Evidence) string {
+	return fmt.Sprintf("key=%s type=%s source=[%s]", ev.Key, ev.Type, canonicalDbReferences(ev.Source.DbReference))
+}
+
+func canonicalDbReferences(refs []DbReference) string {
+	return strings.Join(sortedMap(refs, canonicalDbReference), " ")
+}
+
+func canonicalDbReference(r DbReference) string {
+	props := sortedMap(r.Property, func(p Property) string { return p.Type + "=" + quote(p.Value) })
+	s := fmt.Sprintf("%s:%s{%s}", r.Type, r.ID, strings.Join(props, " "))
+	if molecule := cmp.Or(r.Molecule.ID, r.Molecule.Value); molecule != "" {
+		s += "@" + molecule
+	}
+	if r.EvidenceKeys != "" || len(r.Evidence) > 0 {
+		s += "[" + canonicalEvidence(r.EvidenceKeys, r.Evidence) + "]"
+	}
+	return s
+}
+
+func canonicalReference(r Reference) string {
+	c := r.Citation
+	return fmt.Sprintf("key=%s type=%s date=%s name=%s volume=%s pages=%s-%s publisher=%s city=%s institute=%s country=%s number=%s db=%s "+
+		"title=%s journal=%s editors=[%s] authors=[%s] consortia=[%s] refs=[%s] scope=[%s] source=[%s strains=[%s] refs=[%s]] protein=[%s] gene=[%s] organism=[%s] dbReferences=[%s]",
+		r.Key, quote(c.Type), quote(c.Date), quote(c.Name), quote(c.Volume), c.FirstPage, c.LastPage,
+		quote(c.Publisher), quote(c.City), quote(c.Institute), quote(c.Country), quote(c.Number), quote(c.DB),
+		quote(c.Title), quote(c.Journal.Value),
+		quoteAll(mapSlice(c.EditorList.Person, func(p Person) string { return p.Name })),
+		quoteAll(mapSlice(c.AuthorList.Person, func(p Person) string { return p.Name })),
+		quoteAll(mapSlice(c.AuthorList.Consortium, func(p Consortium) string { return p.Name })),
+		canonicalDbReferences(c.DbReference),
+		strings.Join(sortedMap(r.Scope, quote), " "),
+		canonicalOrganism(r.Source.Organism.Name, r.Source.Organism.DbReference, r.Source.Organism.Lineage),
+		strings.Join(sortedMap(r.Source.Strain, quote), " "),
+		canonicalDbReferences(r.Source.DbReference),
+		strings.Join(mapSlice(r.Protein.Name, func(n Name) string { return n.Type + "=" + quote(n.Value) }), " "),
+		canonicalGeneNames(r.Gene.Name),
+		canonicalOrganismNames(r.Organism.Name),
+		canonicalDbReferences(r.DbReference))
+}
+
+// canonicalLocation renders the span of l followed by the statuses that
+// are set, so that "<1" and "1" render differently.
+func canonicalLocation(l Location) string {
+	begin, end := l.span()
+	s := fmt.Sprintf("%d-%d", begin, end)
+	for _, status := range []struct{ name, value string }{
+		{"position", l.Position.Status},
+		{"begin", l.Begin.Status},
+		{"end", l.End.Status},
+	} {
+		if status.value != "" {
+			s += " " + status.name + "=" + quote(status.value)
+		}
+	}
+	return s
+}
+
+func canonicalComment(c Comment) string {
+	k := c.KineticParameters
+	return fmt.Sprintf("type=%s text=[%s] evidence=[%s] location=%s molecule=%s:%s mass=%v error=%s method=%s "+
+		"reaction=[names=[%s] refs=[%s] ec=%s] enzyme=[%s] ph=%s temperature=%s kinetics=[km=[%s] vmax=[%s] text=%s] "+
+		"events=[%s] isoforms=[%s]%s",
+		quote(c.Type),
+		strings.Join(mapSlice(c.Text, func(t Text) string {
+			return quote(t.Value) + "[" + canonicalEvidence(t.EvidenceKeys, t.Evidence) + "]"
+		}), " "),
+		canonicalEvidence(c.EvidenceKeys, c.Evidence),
+		canonicalLocation(c.Location), c.Molecule.ID, quote(c.Molecule.Value),
+		c.Mass, quote(c.MassError), quote(c.Method),
+		quoteAll(c.Reaction.Name), canonicalDbReferences(c.Reaction.DbReference), c.Reaction.EC,
+		strings.Join(c.Enzyme.EC, " "),
+		quote(c.Ph.Value), quote(c.Temperature.Value),
+		strings.Join(mapSlice(k.Km, func(km Km) string { return quote(km.Value) + km.Unit }), " "),
+		strings.Join(mapSlice(k.Vmax, func(v Vmax) string { return quote(v.Value) + v.Unit }), " "), quote(k.Text),
+		strings.Join(sortedMap(c.Event, func(ev Event) string { return quote(ev.Type) }), " "),
+		strings.Join(mapSlice(c.Isoform, func(iso Isoform) string {
+			return strings.Join(iso.ID, ",") + "(" + quoteAll(iso.Name) + ")=" + iso.Sequence.Type + ":" + iso.Sequence.Ref
+		}), " "),
+		canonicalRNAEditing(c.RNAEditing))
+}
+
+func canonicalRNAEditing(r *RNAEditing) string {
+	if r == nil {
+		return ""
+	}
+	return fmt.Sprintf(" editing=[type=%s positions=%v note=%s]", r.LocationType, r.Positions, quote(r.Note))
+}
+
+func canonicalFeature(f Feature) string {
+	return fmt.Sprintf("%s type=%s id=%s description=%s original=%s variation=[%s] ref=%s evidence=[%s]",
+		canonicalLocation(f.Location), quote(f.Type), f.Id, quote(f.Description), quote(f.Original),
+		strings.Join(mapSlice(f.Variation, func(v Variation) string {
+			return quote(v.Original) + ">" + quote(v.Sequence)
+		}), " "),
+		f.Ref, canonicalEvidence(f.EvidenceKeys, f.Evidence))
+}
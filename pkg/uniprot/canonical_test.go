@@ -0,0 +1,107 @@
+package uniprot
+
+import (
+	"encoding/xml"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// leafPaths appends the dotted paths of the string, number and bool fields
+// reachable from typ, descending into structs, slices and pointers. Types
+// already on the path are not entered again, which breaks the cycle between
+// DbReference and Evidence.
+func leafPaths(typ reflect.Type, prefix string, seen []reflect.Type, paths []string) []string {
+	switch typ.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		return leafPaths(typ.Elem(), prefix, seen, paths)
+	case reflect.Struct:
+		if slices.Contains(seen, typ) {
+			return paths
+		}
+		seen = append(seen, typ)
+		for i := range typ.NumField() {
+			f := typ.Field(i)
+			if f.Type == reflect.TypeFor[xml.Name]() {
+				continue
+			}
+			paths = leafPaths(f.Type, prefix+"."+f.Name, seen, paths)
+		}
+		return paths
+	}
+	return append(paths, strings.TrimPrefix(prefix, "."))
+}
+
+// setPath creates the slice elements and pointers along path in v and, if
+// set is true, gives the leaf a non-zero value.
+func setPath(v reflect.Value, path []string, set bool) {
+	for {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+			continue
+		case reflect.Slice:
+			if v.Len() == 0 {
+				v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+			}
+			v = v.Index(0)
+			continue
+		}
+		if len(path) == 0 {
+			break
+		}
+		v = v.FieldByName(path[0])
+		path = path[1:]
+	}
+	if !set {
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("x")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Float64:
+		v.SetFloat(1.5)
+	default:
+		panic("unhandled kind " + v.Kind().String())
+	}
+}
+
+func TestCanonicalRendersEveryField(t *testing.T) {
+	paths := leafPaths(reflect.TypeFor[Entry](), "", nil, nil)
+	for _, path := range paths {
+		var zero, set Entry
+		setPath(reflect.ValueOf(&zero).Elem(), strings.Split(path, "."), false)
+		setPath(reflect.ValueOf(&set).Elem(), strings.Split(path, "."), true)
+		if zero.Canonical() == set.Canonical() {
+			t.Errorf("Canonical ignores %s", path)
+		}
+	}
+}
+
+func TestCanonicalIgnoresOrder(t *testing.T) {
+	e := sampleEntry(t)
+	shuffled := sampleEntry(t)
+	slices.Reverse(shuffled.Keyword)
+	slices.Reverse(shuffled.DbReference)
+	slices.Reverse(shuffled.Feature)
+	slices.Reverse(shuffled.Comment)
+	slices.Reverse(shuffled.Reference)
+	slices.Reverse(shuffled.Evidence)
+	slices.Reverse(shuffled.Organism.Name)
+	if e.Canonical() != shuffled.Canonical() {
+		t.Error("Canonical depends on the order of unordered collections")
+	}
+
+	slices.Reverse(shuffled.Accession)
+	if e.Canonical() == shuffled.Canonical() {
+		t.Error("Canonical ignores the order of accessions")
+	}
+}
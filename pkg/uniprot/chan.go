@@ -0,0 +1,50 @@
+package uniprot
+
+import "context"
+
+// entriesChanBuffer is the capacity of the entry channel of EntriesChan.
+const entriesChanBuffer = 64
+
+// EntriesChan reads the UniProt entries of an XML file, optionally gzipped,
+// on a new goroutine and sends them on the returned entry channel, for use
+// in channel-based pipelines.
+//
+// The goroutine stops at the end of the file, at the first error, or when
+// ctx is canceled, whichever comes first. It then closes the file and both
+// channels. At most one error is sent, ctx.Err() in the case of
+// cancellation, and the error channel is buffered so that sending it never
+// blocks. Entries already buffered when ctx is canceled are still
+// delivered before the entry channel closes. Callers should receive from
+// the entry channel until it is closed and then check the error channel:
+//
+//	entries, errc := uniprot.EntriesChan(ctx, "uniprot_sprot.xml.gz")
+//	for entry := range entries {
+//		...
+//	}
+//	if err := <-errc; err != nil {
+//		...
+//	}
+//
+// A caller that stops receiving early must cancel ctx so the goroutine can
+// exit.
+func EntriesChan(ctx context.Context, filePath string) (<-chan Entry, <-chan error) {
+	entries := make(chan Entry, entriesChanBuffer)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(errc)
+		defer close(entries)
+		for entry, err := range UniProtEntries(filePath) {
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				errc <- ctx.Err()
+				return
+			}
+		}
+	}()
+	return entries, errc
+}
@@ -0,0 +1,83 @@
+package uniprot
+
+import (
+	"fmt"
+	"math"
+)
+
+// MassTolerance is the largest difference in daltons between the declared
+// and computed sequence mass that CheckSequenceConsistency accepts. UniProt
+// declares masses rounded to whole daltons.
+const MassTolerance = 1.0
+
+// LengthMismatchError reports a declared sequence length that differs from
+// the number of residues.
+type LengthMismatchError struct {
+	Accession string
+	Declared  int
+	Actual    int
+}
+
+func (e *LengthMismatchError) Error() string {
+	return fmt.Sprintf("%s: declared length %d, sequence has %d residues",
+		e.Accession, e.Declared, e.Actual)
+}
+
+// MassDriftWarning reports a declared sequence mass further than
+// MassTolerance from the mass computed from the residues. Unlike the other
+// discrepancies it is a warning: the declared mass may have been computed
+// with slightly different residue masses.
+type MassDriftWarning struct {
+	Accession string
+	Declared  int
+	Computed  float64
+}
+
+func (e *MassDriftWarning) Error() string {
+	return fmt.Sprintf("%s: declared mass %d Da, computed %.2f Da",
+		e.Accession, e.Declared, e.Computed)
+}
+
+// FeatureRangeError reports a feature extending past the end of the
+// sequence.
+type FeatureRangeError struct {
+	Accession string
+	Feature   Feature
+	Length    int
+}
+
+func (e *FeatureRangeError) Error() string {
+	begin, end := e.Feature.Location.span()
+	return fmt.Sprintf("%s: %s feature %d-%d extends past sequence length %d",
+		e.Accession, e.Feature.Type, begin, end, e.Length)
+}
+
+// CheckSequenceConsistency cross-checks the sequence of the entry against
+// its declared length and mass and its features, and returns every
+// discrepancy found: a *LengthMismatchError, a *MassDriftWarning (skipped
+// when the sequence contains ambiguous residues) and a *FeatureRangeError
+// per feature ending past the sequence. Features referring to another
+// sequence are not checked. Use errors.As to tell the hard errors from the
+// mass warning.
+func (e Entry) CheckSequenceConsistency() []error {
+	var errs []error
+	acc := e.PrimaryAccession()
+	seq := e.SequenceString()
+	length := len(seq)
+	if e.Sequence.Length != length {
+		errs = append(errs, &LengthMismatchError{Accession: acc, Declared: e.Sequence.Length, Actual: length})
+	}
+	if mass, ok := e.Sequence.MolecularWeight(); ok && e.Sequence.Mass != 0 &&
+		math.Abs(mass-float64(e.Sequence.Mass)) > MassTolerance {
+		errs = append(errs, &MassDriftWarning{Accession: acc, Declared: e.Sequence.Mass, Computed: mass})
+	}
+	for _, f := range e.Feature {
+		if f.IsExternalRef() {
+			continue
+		}
+		if begin, end := f.Location.span(); max(begin, end) > length {
+			errs = append(errs, &FeatureRangeError{Accession: acc, Feature: f, Length: length})
+		}
+	}
+	return errs
+}
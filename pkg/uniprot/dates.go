@@ -0,0 +1,33 @@
+package uniprot
+
+import (
+	"iter"
+	"time"
+)
+
+// ModifiedTime parses the entry-level modified date, which changes with
+// any update of the entry, annotation included.
+func (e Entry) ModifiedTime() (time.Time, error) {
+	return time.Parse(time.DateOnly, e.Modified)
+}
+
+// SequenceModifiedTime parses the modified date of the sequence. Unlike
+// ModifiedTime it only changes when the sequence itself does, together
+// with Sequence.Version.
+func (e Entry) SequenceModifiedTime() (time.Time, error) {
+	return time.Parse(time.DateOnly, e.Sequence.Modified)
+}
+
+// FilterSequenceChangedSince returns a decorator keeping the entries whose
+// sequence was modified after t, for reprocessing only the sequences that
+// changed since a previous run. Entries whose sequence date is missing or
+// malformed are kept, so that nothing is skipped by mistake.
+func FilterSequenceChangedSince(t time.Time) func(iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	keep := func(e Entry) bool {
+		modified, err := e.SequenceModifiedTime()
+		return err != nil || modified.After(t)
+	}
+	return func(src iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+		return filterEntries(src, keep, nil)
+	}
+}
@@ -0,0 +1,127 @@
+package uniprot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntryDiff describes the changes between two versions of an entry.
+type EntryDiff struct {
+	Accession           string
+	AddedKeywords       []string
+	RemovedKeywords     []string
+	SequenceChanged     bool
+	OldChecksum         string
+	NewChecksum         string
+	AddedFeatures       []Feature
+	RemovedFeatures     []Feature
+	AddedDbReferences   []DbReference
+	RemovedDbReferences []DbReference
+}
+
+// Diff compares two versions of an entry. Features are matched by feature ID,
+// or by type and location when they have none; cross-references are matched
+// by database and ID. The sequences are compared by checksum when both
+// versions carry one.
+func Diff(old, new Entry) EntryDiff {
+	d := EntryDiff{
+		Accession:   new.PrimaryAccession(),
+		OldChecksum: old.Sequence.Checksum,
+		NewChecksum: new.Sequence.Checksum,
+	}
+	if d.Accession == "" {
+		d.Accession = old.PrimaryAccession()
+	}
+
+	keywordValue := func(k Keyword) string { return k.Value }
+	added, removed := setDiff(old.Keyword, new.Keyword, keywordValue)
+	for _, k := range added {
+		d.AddedKeywords = append(d.AddedKeywords, k.Value)
+	}
+	for _, k := range removed {
+		d.RemovedKeywords = append(d.RemovedKeywords, k.Value)
+	}
+
+	if old.Sequence.Checksum != "" && new.Sequence.Checksum != "" {
+		d.SequenceChanged = old.Sequence.Checksum != new.Sequence.Checksum
+	} else {
+		d.SequenceChanged = old.SequenceString() != new.SequenceString()
+	}
+
+	d.AddedFeatures, d.RemovedFeatures = setDiff(old.Feature, new.Feature, featureKey)
+	d.AddedDbReferences, d.RemovedDbReferences = setDiff(old.DbReference, new.DbReference, dbReferenceKey)
+	return d
+}
+
+func dbReferenceKey(ref DbReference) string {
+	return ref.Type + ":" + ref.ID
+}
+
+// setDiff returns the elements of new whose key is not in old, and the
+// elements of old whose key is not in new.
+func setDiff[T any](old, new []T, key func(T) string) (added, removed []T) {
+	oldKeys := make(map[string]bool, len(old))
+	for _, v := range old {
+		oldKeys[key(v)] = true
+	}
+	newKeys := make(map[string]bool, len(new))
+	for _, v := range new {
+		newKeys[key(v)] = true
+		if !oldKeys[key(v)] {
+			added = append(added, v)
+		}
+	}
+	for _, v := range old {
+		if !newKeys[key(v)] {
+			removed = append(removed, v)
+		}
+	}
+	return added, removed
+}
+
+// Empty reports whether the diff records no changes.
+func (d EntryDiff) Empty() bool {
+	return len(d.AddedKeywords) == 0 && len(d.RemovedKeywords) == 0 &&
+		!d.SequenceChanged &&
+		len(d.AddedFeatures) == 0 && len(d.RemovedFeatures) == 0 &&
+		len(d.AddedDbReferences) == 0 && len(d.RemovedDbReferences) == 0
+}
+
+// String renders the diff as a human-readable summary, one change per line.
+func (d EntryDiff) String() string {
+	var sb strings.Builder
+	if d.Empty() {
+		fmt.Fprintf(&sb, "%s: no changes\n", d.Accession)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "%s:\n", d.Accession)
+	if d.SequenceChanged {
+		fmt.Fprintf(&sb, "  ~ sequence (checksum %s -> %s)\n", d.OldChecksum, d.NewChecksum)
+	}
+	for _, k := range d.AddedKeywords {
+		fmt.Fprintf(&sb, "  + keyword %s\n", k)
+	}
+	for _, k := range d.RemovedKeywords {
+		fmt.Fprintf(&sb, "  - keyword %s\n", k)
+	}
+	for _, f := range d.AddedFeatures {
+		fmt.Fprintf(&sb, "  + feature %s\n", describeFeature(f))
+	}
+	for _, f := range d.RemovedFeatures {
+		fmt.Fprintf(&sb, "  - feature %s\n", describeFeature(f))
+	}
+	for _, ref := range d.AddedDbReferences {
+		fmt.Fprintf(&sb, "  + xref %s\n", dbReferenceKey(ref))
+	}
+	for _, ref := range d.RemovedDbReferences {
+		fmt.Fprintf(&sb, "  - xref %s\n", dbReferenceKey(ref))
+	}
+	return sb.String()
+}
+
+func describeFeature(f Feature) string {
+	if f.Description == "" {
+		return featureKey(f)
+	}
+	return featureKey(f) + " " + f.Description
+}
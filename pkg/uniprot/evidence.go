@@ -0,0 +1,102 @@
+package uniprot
+
+import (
+	"cmp"
+	"strings"
+)
+
+// evidenceTypes maps the keys of the entry's evidence definitions to their
+// evidence types (ECO codes).
+func (e Entry) evidenceTypes() map[string]string {
+	types := make(map[string]string, len(e.Evidence))
+	for _, ev := range e.Evidence {
+		types[ev.Key] = ev.Type
+	}
+	return types
+}
+
+// FeatureEvidenceCodes returns the evidence types (ECO codes) supporting f,
+// resolved against the entry's evidence definitions through the feature's
+// evidence keys. The result is empty, not nil, when f has no evidence.
+func (e Entry) FeatureEvidenceCodes(f Feature) []string {
+	types := e.evidenceTypes()
+	codes := []string{}
+	seen := make(map[string]bool)
+	for _, key := range strings.Fields(f.EvidenceKeys) {
+		codes = appendUnique(codes, seen, types[key])
+	}
+	for _, ev := range f.Evidence {
+		if ev.Type != "" {
+			codes = appendUnique(codes, seen, ev.Type)
+		} else {
+			codes = appendUnique(codes, seen, types[ev.Key])
+		}
+	}
+	return codes
+}
+
+// EvidenceTypeCounts counts the evidence references of the entry by
+// evidence type (ECO code), for scoring how much of the annotation is
+// experimentally supported. References are resolved through the entry's
+// evidence definitions, and those that resolve to no type are not counted.
+//
+// The traversal covers the evidence of the recommended, alternative and
+// submitted protein names (full and short), the gene locations, the
+// organism and entry cross-references, the lineage taxa, the comments and
+// their texts, the keywords, and the features. Both the keys of evidence
+// attributes and nested <evidence> elements are counted. The definitions
+// themselves are not counted.
+func (e Entry) EvidenceTypeCounts() map[string]int {
+	types := e.evidenceTypes()
+	counts := make(map[string]int)
+	add := func(keys string, evidence []Evidence) {
+		for _, key := range strings.Fields(keys) {
+			if t := types[key]; t != "" {
+				counts[t]++
+			}
+		}
+		for _, ev := range evidence {
+			if t := cmp.Or(ev.Type, types[ev.Key]); t != "" {
+				counts[t]++
+			}
+		}
+	}
+	addName := func(full FullName, short []ShortName) {
+		add(full.EvidenceKeys, full.Evidence)
+		for _, s := range short {
+			add(s.EvidenceKeys, s.Evidence)
+		}
+	}
+
+	addName(e.Protein.RecommendedName.FullName, e.Protein.RecommendedName.ShortName)
+	for _, n := range e.Protein.AlternativeName {
+		addName(n.FullName, n.ShortName)
+	}
+	for _, n := range e.Protein.SubmittedName {
+		addName(n.FullName, n.ShortName)
+	}
+	for _, g := range e.GeneLocation {
+		add(g.EvidenceKeys, g.Evidence)
+	}
+	for _, refs := range [][]DbReference{e.Organism.DbReference, e.DbReference} {
+		for _, ref := range refs {
+			add(ref.EvidenceKeys, ref.Evidence)
+		}
+	}
+	for _, t := range e.Organism.Lineage.Taxon {
+		add("", t.Evidence)
+	}
+	for _, c := range e.Comment {
+		add(c.EvidenceKeys, c.Evidence)
+		for _, t := range c.Text {
+			add(t.EvidenceKeys, t.Evidence)
+		}
+	}
+	for _, k := range e.Keyword {
+		add(k.EvidenceKeys, k.Evidence)
+	}
+	for _, f := range e.Feature {
+		add(f.EvidenceKeys, f.Evidence)
+	}
+	return counts
+}
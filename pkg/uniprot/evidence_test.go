@@ -0,0 +1,35 @@
+package uniprot
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestEvidenceTypeCounts(t *testing.T) {
+	e := decodeEntry(t, `<entry dataset="Swiss-Prot">
+<accession>P1</accession>
+<protein>
+<recommendedName><fullName evidence="1">Protein one</fullName><shortName evidence="2">P1</shortName></recommendedName>
+<alternativeName><fullName evidence="1 2">Other name</fullName></alternativeName>
+</protein>
+<geneLocation type="plasmid" evidence="2"><name>pX</name></geneLocation>
+<comment type="function" evidence="1"><text evidence="1 2">Does things.</text></comment>
+<dbReference type="PDB" id="1ABC" evidence="2"/>
+<keyword id="KW-0001" evidence="1">Keyword</keyword>
+<feature type="chain" evidence="1"><evidence key="2"/><location><begin position="1"/><end position="3"/></location></feature>
+<feature type="site" evidence="3"><location><position position="2"/></location></feature>
+<evidence type="ECO:0000269" key="1"/>
+<evidence type="ECO:0000305" key="2"/>
+<sequence length="3">MKT</sequence>
+</entry>`)
+
+	got := e.EvidenceTypeCounts()
+	// Key 3 has no definition and is not counted.
+	want := map[string]int{"ECO:0000269": 6, "ECO:0000305": 6}
+	if !maps.Equal(got, want) {
+		t.Errorf("EvidenceTypeCounts = %v, want %v", got, want)
+	}
+	if got := (Entry{}).EvidenceTypeCounts(); len(got) != 0 {
+		t.Errorf("EvidenceTypeCounts of the zero Entry = %v, want empty", got)
+	}
+}
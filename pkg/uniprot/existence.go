@@ -0,0 +1,44 @@
+package uniprot
+
+// ExistenceLevel is the protein existence level (PE) of an entry, from 1
+// (evidence at protein level) to 5 (uncertain). Lower is better supported.
+// ExistenceUnknown sorts after Uncertain, so that a cut such as
+// level <= InferredFromHomology rejects entries of unknown existence.
+type ExistenceLevel int
+
+const (
+	EvidenceAtProteinLevel ExistenceLevel = iota + 1
+	EvidenceAtTranscriptLevel
+	InferredFromHomology
+	Predicted
+	Uncertain
+	ExistenceUnknown
+)
+
+var existenceNames = [...]string{
+	EvidenceAtProteinLevel:    "evidence at protein level",
+	EvidenceAtTranscriptLevel: "evidence at transcript level",
+	InferredFromHomology:      "inferred from homology",
+	Predicted:                 "predicted",
+	Uncertain:                 "uncertain",
+	ExistenceUnknown:          "unknown",
+}
+
+// Level returns the existence level named by the type attribute, or
+// ExistenceUnknown for a missing or unrecognized type.
+func (pe ProteinExistence) Level() ExistenceLevel {
+	for level := EvidenceAtProteinLevel; level <= Uncertain; level++ {
+		if existenceNames[level] == pe.Type {
+			return level
+		}
+	}
+	return ExistenceUnknown
+}
+
+// String returns the name of the level as used in UniProt XML.
+func (l ExistenceLevel) String() string {
+	if l < EvidenceAtProteinLevel || l > ExistenceUnknown {
+		return existenceNames[ExistenceUnknown]
+	}
+	return existenceNames[l]
+}
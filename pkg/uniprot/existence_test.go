@@ -0,0 +1,39 @@
+package uniprot
+
+import "testing"
+
+func TestProteinExistenceLevel(t *testing.T) {
+	tests := []struct {
+		typ  string
+		want ExistenceLevel
+	}{
+		{"evidence at protein level", EvidenceAtProteinLevel},
+		{"evidence at transcript level", EvidenceAtTranscriptLevel},
+		{"inferred from homology", InferredFromHomology},
+		{"predicted", Predicted},
+		{"uncertain", Uncertain},
+		{"", ExistenceUnknown},
+		{"unknown", ExistenceUnknown},
+		{"Predicted", ExistenceUnknown},
+	}
+	for _, tt := range tests {
+		if got := (ProteinExistence{Type: tt.typ}).Level(); got != tt.want {
+			t.Errorf("Level() of %q = %v, want %v", tt.typ, got, tt.want)
+		}
+	}
+	for level := EvidenceAtProteinLevel; level <= Uncertain; level++ {
+		if got := (ProteinExistence{Type: level.String()}).Level(); got != level {
+			t.Errorf("Level() of %q = %v, want %v", level.String(), got, level)
+		}
+	}
+	if EvidenceAtProteinLevel != 1 || Uncertain != 5 {
+		t.Errorf("levels run from %d to %d, want PE 1 to 5", EvidenceAtProteinLevel, Uncertain)
+	}
+	// A quality cut on the level must not let unknown entries through.
+	if level := (Entry{}).ProteinExistence.Level(); level <= Uncertain {
+		t.Errorf("Level() of a missing type = %d, want above Uncertain", level)
+	}
+	if got := ExistenceLevel(0).String(); got != "unknown" {
+		t.Errorf("String() of level 0 = %q, want unknown", got)
+	}
+}
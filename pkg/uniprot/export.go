@@ -0,0 +1,135 @@
+package uniprot
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// exportWindow is the number of entries per worker that ExportConcurrent
+// lets be in flight between decoding and writing.
+const exportWindow = 4
+
+// ExportConcurrent decodes the UniProt entries of filePath on one
+// goroutine, runs transform on each of them on a pool of workers, and
+// writes the results to w in input order, so the output is the same as
+// that of a sequential loop. workers <= 0 means runtime.GOMAXPROCS(0).
+//
+// At most a few entries per worker are in flight at a time; the results
+// that finish ahead of an earlier entry wait in a reorder buffer of that
+// bounded size. ExportConcurrent stops at the first transform error in
+// input order, at the first decoding or write error, or when ctx is
+// canceled, and returns that error or ctx.Err(). The output then holds the
+// results of the entries before the failure.
+func ExportConcurrent(ctx context.Context, filePath string, workers int, transform func(Entry) ([]byte, error), w io.Writer) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		seq   int
+		entry Entry
+	}
+	type result struct {
+		seq       int
+		accession string
+		data      []byte
+		err       error
+	}
+	jobs := make(chan job, workers)
+	results := make(chan result, workers)
+	// slots bounds the entries between decoding and writing; the decoder
+	// takes a slot for each entry and the writer returns it.
+	slots := make(chan struct{}, exportWindow*workers)
+
+	var decodeErr error
+	decoded := make(chan struct{})
+	go func() {
+		defer close(decoded)
+		defer close(jobs)
+		seq := 0
+		for entry, err := range UniProtEntries(filePath) {
+			if err != nil {
+				decodeErr = err
+				return
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- job{seq, entry}:
+			case <-ctx.Done():
+				return
+			}
+			seq++
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				data, err := transform(j.entry)
+				select {
+				case results <- result{j.seq, j.entry.PrimaryAccession(), data, err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	bw := bufio.NewWriter(w)
+	pending := make(map[int]result)
+	next := 0
+	var firstErr error
+	for r := range results {
+		if firstErr != nil {
+			continue // drain until the workers have stopped
+		}
+		pending[r.seq] = r
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if r.err != nil {
+				firstErr = fmt.Errorf("transforming %s: %w", r.accession, r.err)
+			} else if _, err := bw.Write(r.data); err != nil {
+				firstErr = err
+			}
+			if firstErr != nil {
+				cancel()
+				break
+			}
+			<-slots
+			next++
+		}
+	}
+
+	<-decoded
+	flushErr := bw.Flush()
+	switch {
+	case firstErr != nil:
+		return firstErr
+	case decodeErr != nil:
+		return decodeErr
+	case ctx.Err() != nil:
+		return ctx.Err()
+	}
+	return flushErr
+}
@@ -0,0 +1,185 @@
+package uniprot
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FASTALineWidth is the number of residues per line written by WriteFASTA.
+const FASTALineWidth = 60
+
+// FASTAHeader returns the header line of the entry in UniProt's FASTA
+// format, without the leading ">":
+//
+//	sp|P69905|HBA_HUMAN Hemoglobin subunit alpha OS=Homo sapiens OX=9606 GN=HBA1 PE=1 SV=2
+//
+// The database is "sp" for Swiss-Prot and "tr" for TrEMBL entries. Fields
+// with no value are left out.
+func (e Entry) FASTAHeader() string {
+	db := "tr"
+	if e.IsReviewed() {
+		db = "sp"
+	}
+	var sb strings.Builder
+	sb.WriteString(db + "|" + e.PrimaryAccession() + "|" + e.EntryName())
+	if name := e.ProteinName(); name != "" {
+		sb.WriteString(" " + name)
+	}
+	if organism := e.ScientificName(); organism != "" {
+		sb.WriteString(" OS=" + organism)
+	}
+	if ox := e.TaxID(); ox != 0 {
+		sb.WriteString(" OX=" + strconv.Itoa(ox))
+	}
+	if gn := e.GeneName(); gn != "" {
+		sb.WriteString(" GN=" + gn)
+	}
+	if pe := e.ProteinExistence.Level(); pe != ExistenceUnknown {
+		sb.WriteString(" PE=" + strconv.Itoa(int(pe)))
+	}
+	if e.Sequence.Version != 0 {
+		sb.WriteString(" SV=" + strconv.Itoa(e.Sequence.Version))
+	}
+	return sb.String()
+}
+
+// writeFASTA writes the entry as a FASTA record with lines of width
+// residues.
+func writeFASTA(bw *bufio.Writer, entry Entry, width int) {
+	bw.WriteString(">" + entry.FASTAHeader() + "\n")
+	writeWrapped(bw, entry.SequenceString(), width)
+}
+
+// writeWrapped writes seq in lines of width residues, or on a single line
+// if width is 0 or less.
+func writeWrapped(bw *bufio.Writer, seq string, width int) {
+	if width <= 0 {
+		width = len(seq)
+	}
+	for len(seq) > width {
+		bw.WriteString(seq[:width] + "\n")
+		seq = seq[width:]
+	}
+	if seq != "" {
+		bw.WriteString(seq + "\n")
+	}
+}
+
+// WriteFASTA writes the entries as FASTA records with UniProt-style headers
+// (see FASTAHeader) and FASTALineWidth residues per line.
+func WriteFASTA(w io.Writer, entries iter.Seq2[Entry, error]) error {
+	return WriteFASTAWrapped(w, FASTALineWidth, entries)
+}
+
+// WriteFASTAWrapped is like WriteFASTA but wraps the sequences at width
+// residues per line, e.g. 80 for tools that expect it. A width of 0 writes
+// each sequence on a single line.
+func WriteFASTAWrapped(w io.Writer, width int, entries iter.Seq2[Entry, error]) error {
+	bw := bufio.NewWriter(w)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		writeFASTA(bw, entry, width)
+	}
+	return bw.Flush()
+}
+
+// WriteFeatureFASTA writes one FASTA record per feature of the given types,
+// holding the residues the feature covers, with headers such as
+//
+//	P12345/25-130 type="domain" Protein kinase
+//
+// Features with an unknown begin or end, referring to another sequence or
+// extending past the sequence are skipped, as are entries without a
+// sequence.
+func WriteFeatureFASTA(w io.Writer, featureTypes []string, entries iter.Seq2[Entry, error]) error {
+	bw := bufio.NewWriter(w)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		acc := entry.PrimaryAccession()
+		for _, f := range entry.Feature {
+			if !slices.Contains(featureTypes, f.Type) {
+				continue
+			}
+			seq, err := entry.FeatureSequence(f)
+			if err != nil || seq == "" {
+				continue
+			}
+			begin, end := f.Location.span()
+			bw.WriteString(">" + acc + "/" + strconv.Itoa(begin) + "-" + strconv.Itoa(end) +
+				" type=" + strconv.Quote(f.Type))
+			if f.Description != "" {
+				bw.WriteString(" " + f.Description)
+			}
+			bw.WriteString("\n")
+			writeWrapped(bw, seq, FASTALineWidth)
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteFASTASharded writes the entries as FASTA records like WriteFASTA,
+// split over files of maxEntries records each; only the last shard may hold
+// fewer. Shards are numbered from 1 and filled in input order, so the same
+// input always yields the same shards. If dirOrPrefix is an existing
+// directory the shards are named "shard-0001.fasta" and so on inside it,
+// otherwise dirOrPrefix is used as a path prefix, as in
+// "out/human-0001.fasta". WriteFASTASharded returns the paths of the files
+// written, including a partial shard when an error stops it.
+func WriteFASTASharded(dirOrPrefix string, maxEntries int, entries iter.Seq2[Entry, error]) (files []string, err error) {
+	if maxEntries <= 0 {
+		return nil, fmt.Errorf("uniprot: invalid shard size %d", maxEntries)
+	}
+	prefix := dirOrPrefix + "-"
+	if info, err := os.Stat(dirOrPrefix); err == nil && info.IsDir() {
+		prefix = filepath.Join(dirOrPrefix, "shard-")
+	}
+
+	var file *os.File
+	var bw *bufio.Writer
+	closeShard := func() error {
+		if file == nil {
+			return nil
+		}
+		err := errors.Join(bw.Flush(), file.Close())
+		file = nil
+		return err
+	}
+	defer func() {
+		if cerr := closeShard(); err == nil {
+			err = cerr
+		}
+	}()
+
+	n := 0
+	for entry, err := range entries {
+		if err != nil {
+			return files, err
+		}
+		if n%maxEntries == 0 {
+			if err := closeShard(); err != nil {
+				return files, err
+			}
+			path := fmt.Sprintf("%s%04d.fasta", prefix, len(files)+1)
+			if file, err = os.Create(path); err != nil {
+				return files, err
+			}
+			files = append(files, path)
+			bw = bufio.NewWriter(file)
+		}
+		writeFASTA(bw, entry, FASTALineWidth)
+		n++
+	}
+	return files, nil
+}
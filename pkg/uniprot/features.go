@@ -0,0 +1,350 @@
+package uniprot
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrExternalRef is returned by FeatureSequence for features whose
+	// coordinates refer to another sequence.
+	ErrExternalRef = errors.New("uniprot: feature refers to another sequence")
+	// ErrUnknownPosition is returned by FeatureSequence for features with
+	// an unknown begin or end.
+	ErrUnknownPosition = errors.New("uniprot: feature position unknown")
+)
+
+// span returns the first and last residue of the location. A single
+// position is reported as a span of length one.
+func (l Location) span() (begin, end int) {
+	if l.Position.Value != 0 {
+		return l.Position.Value, l.Position.Value
+	}
+	return l.Begin.Position, l.End.Position
+}
+
+// bounds is like span but also reports whether both ends are known.
+// Ends with status "unknown" carry no position and decode as zero.
+func (l Location) bounds() (begin, end int, ok bool) {
+	begin, end = l.span()
+	return begin, end, begin > 0 && end >= begin
+}
+
+// LocationStatus returns the status of the feature's begin and end, one of
+// "certain", "uncertain", "less than", "greater than" or "unknown". A
+// missing status attribute means "certain", as in the UniProt schema. For a
+// single-position feature both results are the status of the position.
+func (f Feature) LocationStatus() (begin, end string) {
+	l := f.Location
+	if l.Position.Value != 0 || l.Position.Status != "" {
+		status := cmp.Or(l.Position.Status, "certain")
+		return status, status
+	}
+	return cmp.Or(l.Begin.Status, "certain"), cmp.Or(l.End.Status, "certain")
+}
+
+// IsCertain reports whether both ends of the feature are known exactly, so
+// that its coordinates may be used as they are, e.g. to extract its
+// residues. Features with fuzzy or unknown boundaries, or with no position
+// at all, are not certain.
+func (f Feature) IsCertain() bool {
+	begin, end := f.LocationStatus()
+	_, _, known := f.Location.bounds()
+	return begin == "certain" && end == "certain" && known
+}
+
+// overlaps reports whether the location shares at least one residue with
+// the inclusive range begin..end.
+func (l Location) overlaps(begin, end int) bool {
+	b, e, ok := l.bounds()
+	return ok && b <= end && begin <= e
+}
+
+// FeaturesAt returns the features covering residue pos, sorted by start
+// position. Features with an unknown begin or end are never included.
+func (e Entry) FeaturesAt(pos int) []Feature {
+	return e.FeaturesOverlapping(pos, pos)
+}
+
+// FeaturesOverlapping returns the features sharing at least one residue with
+// the inclusive range begin..end, sorted by start position. Features with an
+// unknown begin or end are never included.
+func (e Entry) FeaturesOverlapping(begin, end int) []Feature {
+	var features []Feature
+	for _, f := range e.Feature {
+		if f.Location.overlaps(begin, end) {
+			features = append(features, f)
+		}
+	}
+	slices.SortStableFunc(features, func(a, b Feature) int {
+		aBegin, _ := a.Location.span()
+		bBegin, _ := b.Location.span()
+		return cmp.Compare(aBegin, bBegin)
+	})
+	return features
+}
+
+// CommentsInRegion returns the comments whose location overlaps the
+// inclusive range begin..end, using the same test as FeaturesOverlapping.
+// Comments without a location, which apply to the whole protein, are not
+// included.
+func (e Entry) CommentsInRegion(begin, end int) []Comment {
+	var comments []Comment
+	for _, c := range e.Comment {
+		if c.Location.overlaps(begin, end) {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// IsExternalRef reports whether the feature carries a ref attribute. Such
+// features, typically sequence conflicts, describe a sequence reported in a
+// citation (ref="3" is reference 3) or another entry, and their coordinates
+// may not apply to the entry's own sequence.
+func (f Feature) IsExternalRef() bool {
+	return f.Ref != ""
+}
+
+// FeatureSequence returns the residues of the entry's sequence covered by f.
+// It returns ErrExternalRef for features with a ref attribute, since their
+// coordinates belong to another sequence, ErrUnknownPosition for features
+// with an unknown end, and an error if f extends past the sequence.
+func (e Entry) FeatureSequence(f Feature) (string, error) {
+	if f.IsExternalRef() {
+		return "", ErrExternalRef
+	}
+	begin, end, ok := f.Location.bounds()
+	if !ok {
+		return "", ErrUnknownPosition
+	}
+	seq := e.SequenceString()
+	if end > len(seq) {
+		return "", fmt.Errorf("feature %d-%d extends past sequence of length %d", begin, end, len(seq))
+	}
+	return seq[begin-1 : end], nil
+}
+
+// MaskSequence returns the entry's sequence with the residues covered by
+// features of the given types, such as "signal peptide" or "transmembrane
+// region", replaced by maskChar (hard masking, typically 'X'). A maskChar
+// of 0 selects soft masking instead: covered residues are lowercased and
+// the rest uppercased. Overlapping features mask their union, and a
+// single-position feature masks one residue. Features with an unknown
+// begin or end, or referring to another sequence, mask nothing, and
+// features extending past the sequence are clipped to it.
+func (e Entry) MaskSequence(featureTypes []string, maskChar byte) string {
+	seq := []byte(e.SequenceString())
+	masked := make([]bool, len(seq))
+	for _, f := range e.Feature {
+		if !slices.Contains(featureTypes, f.Type) || f.IsExternalRef() {
+			continue
+		}
+		begin, end, ok := f.Location.bounds()
+		if !ok {
+			continue
+		}
+		for i := begin - 1; i < min(end, len(seq)); i++ {
+			masked[i] = true
+		}
+	}
+	for i, c := range seq {
+		switch {
+		case maskChar == 0 && masked[i]:
+			seq[i] = byte(unicode.ToLower(rune(c)))
+		case maskChar == 0:
+			seq[i] = byte(unicode.ToUpper(rune(c)))
+		case masked[i]:
+			seq[i] = maskChar
+		}
+	}
+	return string(seq)
+}
+
+// FeatureRow is a flattened feature. Begin and End are -1 where the
+// position is unknown; for single-position features Begin equals End.
+type FeatureRow struct {
+	Accession   string
+	Type        string
+	Begin       int
+	End         int
+	Description string
+	FTId        string
+}
+
+// FeatureTable flattens the features of the entry into rows.
+func (e Entry) FeatureTable() []FeatureRow {
+	acc := e.PrimaryAccession()
+	rows := make([]FeatureRow, 0, len(e.Feature))
+	for _, f := range e.Feature {
+		begin, end := f.Location.span()
+		rows = append(rows, FeatureRow{
+			Accession:   acc,
+			Type:        f.Type,
+			Begin:       knownPosition(begin),
+			End:         knownPosition(end),
+			Description: f.Description,
+			FTId:        f.Id,
+		})
+	}
+	return rows
+}
+
+// knownPosition maps the zero value of a missing or unknown position to -1.
+func knownPosition(pos int) int {
+	if pos == 0 {
+		return -1
+	}
+	return pos
+}
+
+// featureKey identifies a feature across releases: by its feature ID when it
+// has one, and by type and location otherwise.
+func featureKey(f Feature) string {
+	if f.Id != "" {
+		return f.Id
+	}
+	begin, end := f.Location.span()
+	return f.Type + ":" + strconv.Itoa(begin) + "-" + strconv.Itoa(end)
+}
+
+// featuresOfType returns the features of e whose type is featureType.
+func (e Entry) featuresOfType(featureType string) []Feature {
+	var features []Feature
+	for _, f := range e.Feature {
+		if f.Type == featureType {
+			features = append(features, f)
+		}
+	}
+	return features
+}
+
+// MutagenesisSites returns the "mutagenesis site" features of the entry.
+// The functional effect of each mutation is given in its Description.
+func (e Entry) MutagenesisSites() []Feature {
+	return e.featuresOfType("mutagenesis site")
+}
+
+// LargestDomain returns the "domain" feature spanning the most residues,
+// with ties going to the one that starts first. Only ranged features with a
+// known begin and end are considered; ok is false if there are none.
+func (e Entry) LargestDomain() (domain Feature, ok bool) {
+	bestLen, bestBegin := 0, 0
+	for _, f := range e.featuresOfType("domain") {
+		begin, end, known := f.Location.bounds()
+		if !known || f.Location.Position.Value != 0 {
+			continue
+		}
+		n := end - begin + 1
+		if !ok || n > bestLen || n == bestLen && begin < bestBegin {
+			domain, ok, bestLen, bestBegin = f, true, n, begin
+		}
+	}
+	return domain, ok
+}
+
+// MutationString renders the original and variant residues of a feature
+// such as "K123A", "KR123-124AA" or "K123del" for a deletion. Alternative
+// variations are separated by slashes, as in "K123A/R".
+func (f Feature) MutationString() string {
+	begin, end := f.Location.span()
+	var sb strings.Builder
+	sb.WriteString(f.Original)
+	sb.WriteString(strconv.Itoa(begin))
+	if end != begin {
+		sb.WriteByte('-')
+		sb.WriteString(strconv.Itoa(end))
+	}
+	var variations []string
+	for _, v := range f.Variation {
+		if v.Sequence != "" {
+			variations = append(variations, v.Sequence)
+		}
+	}
+	if len(variations) == 0 {
+		sb.WriteString("del")
+	} else {
+		sb.WriteString(strings.Join(variations, "/"))
+	}
+	return sb.String()
+}
+
+// MergeFeatures returns the features of type featureType with overlapping
+// or adjacent ranges coalesced into single spanning features, sorted by
+// start position. A merged feature takes the fields of its first member,
+// joins the distinct descriptions with "; " and drops the feature ID, which
+// no longer identifies it. Point features and features with an unknown
+// begin or end are returned unchanged.
+func (e Entry) MergeFeatures(featureType string) []Feature {
+	var merged, ranges []Feature
+	for _, f := range e.featuresOfType(featureType) {
+		if _, _, ok := f.Location.bounds(); ok && f.Location.Position.Value == 0 {
+			ranges = append(ranges, f)
+		} else {
+			merged = append(merged, f)
+		}
+	}
+	slices.SortStableFunc(ranges, func(a, b Feature) int {
+		aBegin, aEnd := a.Location.span()
+		bBegin, bEnd := b.Location.span()
+		return cmp.Or(cmp.Compare(aBegin, bBegin), cmp.Compare(aEnd, bEnd))
+	})
+
+	for i := 0; i < len(ranges); {
+		cur := ranges[i]
+		_, end := cur.Location.span()
+		seen := make(map[string]bool)
+		descriptions := appendUnique(nil, seen, cur.Description)
+		j := i + 1
+		for ; j < len(ranges); j++ {
+			begin, next := ranges[j].Location.span()
+			if begin > end+1 {
+				break
+			}
+			end = max(end, next)
+			descriptions = appendUnique(descriptions, seen, ranges[j].Description)
+		}
+		if j > i+1 {
+			cur.Location.End.Position = end
+			cur.Location.End.Status = ""
+			cur.Description = strings.Join(descriptions, "; ")
+			cur.Id = ""
+		}
+		merged = append(merged, cur)
+		i = j
+	}
+
+	slices.SortStableFunc(merged, func(a, b Feature) int {
+		aBegin, _ := a.Location.span()
+		bBegin, _ := b.Location.span()
+		return cmp.Compare(aBegin, bBegin)
+	})
+	return merged
+}
+
+// SequenceConflicts returns the "sequence conflict" features of the entry,
+// which record differences between the entry's sequence and those reported
+// in the cited references or other databases.
+func (e Entry) SequenceConflicts() []Feature {
+	return e.featuresOfType("sequence conflict")
+}
+
+// ConflictString renders a sequence conflict like MutationString, followed
+// by the source of the conflicting sequence, as in "A123G (Ref.3)" for
+// reference 3. A ref that is not a reference number, such as another
+// sequence's identifier, is shown as is; no suffix is added without a ref.
+func (f Feature) ConflictString() string {
+	s := f.MutationString()
+	if !f.IsExternalRef() {
+		return s
+	}
+	if _, err := strconv.Atoi(f.Ref); err == nil {
+		return s + " (Ref." + f.Ref + ")"
+	}
+	return s + " (" + f.Ref + ")"
+}
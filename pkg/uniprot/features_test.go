@@ -0,0 +1,120 @@
+package uniprot
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// featureSummary renders features as "begin-end id description" lines.
+func featureSummary(features []Feature) string {
+	var lines []string
+	for _, f := range features {
+		begin, end := f.Location.span()
+		lines = append(lines, fmt.Sprintf("%d-%d %s %s", begin, end, f.Id, f.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestMergeFeatures(t *testing.T) {
+	e := decodeEntry(t, `<entry>
+<accession>P1</accession>
+<feature type="region" id="R1" description="Disordered">
+  <location><begin position="21"/><end position="30"/></location>
+</feature>
+<feature type="region" id="R2" description="Disordered">
+  <location><begin position="10"/><end position="20"/></location>
+</feature>
+<feature type="region" id="R3" description="Interaction with DNA">
+  <location><begin position="25"/><end position="35"/></location>
+</feature>
+<feature type="region" id="R4" description="Disordered">
+  <location><begin position="50"/><end position="70"/></location>
+</feature>
+<feature type="region" id="R5" description="Basic">
+  <location><begin position="55"/><end position="60"/></location>
+</feature>
+<feature type="region" id="R6" description="Isolated">
+  <location><begin position="80"/><end position="90"/></location>
+</feature>
+<feature type="region" id="R7" description="Point">
+  <location><position position="65"/></location>
+</feature>
+<feature type="region" id="R8" description="Open-ended">
+  <location><begin position="85"/><end status="unknown"/></location>
+</feature>
+<feature type="domain" id="D1" description="Not a region">
+  <location><begin position="36"/><end position="49"/></location>
+</feature>
+</entry>`)
+	want := strings.Join([]string{
+		"10-35  Disordered; Interaction with DNA",
+		"50-70  Disordered; Basic",
+		"65-65 R7 Point",
+		"80-90 R6 Isolated",
+		"85-0 R8 Open-ended",
+	}, "\n")
+	if got := featureSummary(e.MergeFeatures("region")); got != want {
+		t.Errorf("MergeFeatures() =\n%s\nwant\n%s", got, want)
+	}
+	if got := e.MergeFeatures("signal peptide"); got != nil {
+		t.Errorf("MergeFeatures() of a missing type = %v, want nil", got)
+	}
+	// The entry's own features are left as they are.
+	if got := featureSummary(e.Feature[:1]); got != "21-30 R1 Disordered" {
+		t.Errorf("first feature after merging = %q", got)
+	}
+}
+
+func TestMaskSequence(t *testing.T) {
+	e := decodeEntry(t, `<entry>
+<accession>P1</accession>
+<feature type="signal peptide">
+  <location><begin position="1"/><end position="3"/></location>
+</feature>
+<feature type="transmembrane region">
+  <location><begin position="2"/><end position="5"/></location>
+</feature>
+<feature type="site">
+  <location><position position="8"/></location>
+</feature>
+<feature type="transmembrane region">
+  <location><begin position="10"/><end position="20"/></location>
+</feature>
+<feature type="transmembrane region">
+  <location><begin position="6"/><end status="unknown"/></location>
+</feature>
+<feature type="site" ref="P1-2">
+  <location><position position="9"/></location>
+</feature>
+<feature type="domain">
+  <location><begin position="6"/><end position="7"/></location>
+</feature>
+<sequence length="12">MKTAYiAKQRQI</sequence>
+</entry>`)
+	types := []string{"signal peptide", "transmembrane region", "site"}
+	tests := []struct {
+		name     string
+		types    []string
+		maskChar byte
+		want     string
+	}{
+		// Overlapping features mask their union, the site masks residue
+		// 8 only, and the feature past the end is clipped to 10-12. The
+		// open-ended region and the site on another sequence mask nothing.
+		{"hard", types, 'X', "XXXXXiAXQXXX"},
+		{"soft", types, 0, "mktayIAkQrqi"},
+		{"single type", []string{"signal peptide"}, '-', "---AYiAKQRQI"},
+		{"no types", nil, 'X', "MKTAYiAKQRQI"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := e.MaskSequence(tt.types, tt.maskChar); got != tt.want {
+				t.Errorf("MaskSequence(%q, %q) = %s, want %s", tt.types, tt.maskChar, got, tt.want)
+			}
+		})
+	}
+	if got := e.SequenceString(); got != "MKTAYiAKQRQI" {
+		t.Errorf("sequence after masking = %s", got)
+	}
+}
@@ -0,0 +1,204 @@
+package uniprot
+
+import (
+	"encoding/xml"
+	"iter"
+	"strconv"
+)
+
+// FieldMask selects the sub-elements of an entry decoded by
+// UniProtEntriesFields. The entry's attributes are always decoded.
+type FieldMask uint32
+
+const (
+	FieldAccession FieldMask = 1 << iota
+	FieldName
+	FieldProtein
+	FieldGene
+	FieldOrganism
+	FieldOrganismHost
+	FieldGeneLocation
+	FieldReference
+	FieldComment
+	FieldDbReference
+	FieldProteinExistence
+	FieldKeyword
+	FieldFeature
+	FieldEvidence
+	FieldSequence
+
+	FieldAll FieldMask = 1<<iota - 1
+)
+
+var entryFields = map[string]FieldMask{
+	"accession":        FieldAccession,
+	"name":             FieldName,
+	"protein":          FieldProtein,
+	"gene":             FieldGene,
+	"organism":         FieldOrganism,
+	"organismHost":     FieldOrganismHost,
+	"geneLocation":     FieldGeneLocation,
+	"reference":        FieldReference,
+	"comment":          FieldComment,
+	"dbReference":      FieldDbReference,
+	"proteinExistence": FieldProteinExistence,
+	"keyword":          FieldKeyword,
+	"feature":          FieldFeature,
+	"evidence":         FieldEvidence,
+	"sequence":         FieldSequence,
+}
+
+// UniProtEntriesFields is like UniProtEntries but only decodes the
+// sub-elements of each entry selected by fields; the others are skipped
+// without being built, and the corresponding Entry fields stay zero. For
+// sequence-only work, skipping the references, comments and features
+// saves a large share of the decoding time and memory; see
+// BenchmarkUniProtEntriesFields.
+func UniProtEntriesFields(filePath string, fields FieldMask) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		r, err := Open(filePath)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+		defer r.Close()
+
+		cfg := defaultConfig()
+		err = eachStart(&cfg, r, "entry", func(decoder *xml.Decoder, start xml.StartElement, _ int64) error {
+			entry, err := decodeEntryFields(decoder, start, fields)
+			if err != nil {
+				return err
+			}
+			if !yield(entry, nil) {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			yield(Entry{}, decodeError(filePath, err))
+		}
+	}
+}
+
+// decodeEntryFields decodes the entry element opened by start, keeping only
+// the sub-elements selected by fields.
+func decodeEntryFields(decoder *xml.Decoder, start xml.StartElement, fields FieldMask) (Entry, error) {
+	var entry Entry
+	entry.XMLName = start.Name
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "dataset":
+			entry.Dataset = attr.Value
+		case "created":
+			entry.Created = attr.Value
+		case "modified":
+			entry.Modified = attr.Value
+		case "version":
+			entry.Version, _ = strconv.Atoi(attr.Value)
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return entry, err
+		}
+		var child xml.StartElement
+		switch t := token.(type) {
+		case xml.EndElement:
+			return entry, nil
+		case xml.StartElement:
+			child = t
+		default:
+			continue
+		}
+
+		if fields&entryFields[child.Name.Local] == 0 {
+			err = decoder.Skip()
+		} else {
+			err = decodeEntryField(decoder, child, &entry)
+		}
+		if err != nil {
+			return entry, err
+		}
+	}
+}
+
+// decodeEntryField decodes the sub-element opened by start into entry.
+func decodeEntryField(decoder *xml.Decoder, start xml.StartElement, entry *Entry) error {
+	switch start.Name.Local {
+	case "accession":
+		return decodeAppend(decoder, start, &entry.Accession)
+	case "name":
+		return decodeAppend(decoder, start, &entry.Name)
+	case "protein":
+		return decoder.DecodeElement(&entry.Protein, &start)
+	case "gene":
+		return decodeAppend(decoder, start, &entry.Gene)
+	case "organism":
+		return decoder.DecodeElement(&entry.Organism, &start)
+	case "organismHost":
+		return decodeAppend(decoder, start, &entry.OrganismHost)
+	case "geneLocation":
+		return decodeAppend(decoder, start, &entry.GeneLocation)
+	case "reference":
+		return decodeAppend(decoder, start, &entry.Reference)
+	case "comment":
+		return decodeAppend(decoder, start, &entry.Comment)
+	case "dbReference":
+		return decodeAppend(decoder, start, &entry.DbReference)
+	case "proteinExistence":
+		return decoder.DecodeElement(&entry.ProteinExistence, &start)
+	case "keyword":
+		return decodeAppend(decoder, start, &entry.Keyword)
+	case "feature":
+		return decodeAppend(decoder, start, &entry.Feature)
+	case "evidence":
+		return decodeAppend(decoder, start, &entry.Evidence)
+	case "sequence":
+		return decoder.DecodeElement(&entry.Sequence, &start)
+	}
+	return decoder.Skip()
+}
+
+// decodeAppend decodes the element opened by start and appends it to list.
+func decodeAppend[T any](decoder *xml.Decoder, start xml.StartElement, list *[]T) error {
+	var v T
+	if err := decoder.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	*list = append(*list, v)
+	return nil
+}
+
+// SeqRecord is the minimal projection of an entry needed for sequence work.
+type SeqRecord struct {
+	Accession string
+	Name      string
+	Sequence  string
+	TaxID     int
+}
+
+// Sequences returns an iterator over the sequences of an XML file,
+// optionally gzipped. Only the accessions, entry name, organism and
+// sequence of each entry are decoded.
+func Sequences(filePath string) iter.Seq2[SeqRecord, error] {
+	const fields = FieldAccession | FieldName | FieldOrganism | FieldSequence
+	return func(yield func(SeqRecord, error) bool) {
+		for entry, err := range UniProtEntriesFields(filePath, fields) {
+			if err != nil {
+				yield(SeqRecord{}, err)
+				return
+			}
+			rec := SeqRecord{
+				Accession: entry.PrimaryAccession(),
+				Name:      entry.EntryName(),
+				Sequence:  entry.Sequence.Value,
+				TaxID:     entry.TaxID(),
+			}
+			if !yield(rec, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package uniprot
+
+import (
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// benchmarkEntries is the number of entries in the file built by
+// benchmarkFile.
+const benchmarkEntries = 1000
+
+// benchmarkFile writes an uncompressed XML file of benchmarkEntries copies
+// of the first entry of sampleFile and returns its path. The benchmark
+// timer is reset afterwards.
+func benchmarkFile(b *testing.B) string {
+	b.Helper()
+	data, err := os.ReadFile(sampleFile)
+	if err != nil {
+		b.Fatal(err)
+	}
+	s := string(data)
+	begin := strings.Index(s, "<entry")
+	end := strings.Index(s, "</entry>") + len("</entry>")
+	path := writeTemp(b, "bench.xml", []byte(wrapEntries(strings.Repeat(s[begin:end]+"\n", benchmarkEntries))))
+	b.ResetTimer()
+	return path
+}
+
+// drain ranges over seq and fails the benchmark on error or on a wrong
+// number of elements.
+func drain[T any](b *testing.B, seq func(func(T, error) bool)) {
+	n := 0
+	for _, err := range seq {
+		if err != nil {
+			b.Fatal(err)
+		}
+		n++
+	}
+	if n != benchmarkEntries {
+		b.Fatalf("read %d entries, want %d", n, benchmarkEntries)
+	}
+}
+
+func TestUniProtEntriesFields(t *testing.T) {
+	full := sampleEntry(t)
+	e := readAll(t, UniProtEntriesFields(sampleFile, FieldAccession|FieldSequence))[0]
+	if !slices.Equal(e.Accession, full.Accession) || e.Sequence.Value != full.Sequence.Value {
+		t.Errorf("projected entry %v %q, want %v %q", e.Accession, e.Sequence.Value, full.Accession, full.Sequence.Value)
+	}
+	if e.Dataset != full.Dataset {
+		t.Errorf("Dataset = %q, want %q", e.Dataset, full.Dataset)
+	}
+	if len(e.Reference) != 0 || len(e.Feature) != 0 || len(e.Comment) != 0 || e.TaxID() != 0 {
+		t.Errorf("unselected fields were decoded: %d references, %d features, %d comments", len(e.Reference), len(e.Feature), len(e.Comment))
+	}
+}
+
+func TestUniProtEntriesFieldsAll(t *testing.T) {
+	full := readAll(t, UniProtEntries(sampleFile))
+	all := readAll(t, UniProtEntriesFields(sampleFile, FieldAll))
+	if !reflect.DeepEqual(all, full) {
+		t.Error("entries decoded with FieldAll differ from UniProtEntries")
+	}
+}
+
+func BenchmarkUniProtEntries(b *testing.B) {
+	path := benchmarkFile(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		drain(b, UniProtEntries(path))
+	}
+}
+
+func BenchmarkUniProtEntriesFields(b *testing.B) {
+	path := benchmarkFile(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		drain(b, UniProtEntriesFields(path, FieldAccession|FieldSequence))
+	}
+}
+
+func TestSequences(t *testing.T) {
+	want := []SeqRecord{
+		{Accession: "P69905", Name: "HBA_HUMAN", Sequence: "MVLSPADKTNVKAAWGKVGA", TaxID: 9606},
+		{Accession: "Q00001", Name: "Q00001_ECOLI", Sequence: "MKKLA", TaxID: 562},
+	}
+	if got := readAll(t, Sequences(sampleFile)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Sequences() = %+v, want %+v", got, want)
+	}
+}
+
+func BenchmarkSequences(b *testing.B) {
+	path := benchmarkFile(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		drain(b, Sequences(path))
+	}
+}
@@ -0,0 +1,40 @@
+package uniprot
+
+// FlatRecord is a flat projection of the commonly used scalar and list
+// fields of an entry, suited to wide-table exports.
+type FlatRecord struct {
+	Accession        string
+	EntryName        string
+	GeneName         string
+	ProteinName      string
+	Organism         string
+	TaxID            int
+	Length           int
+	Mass             int
+	Reviewed         bool
+	ProteinExistence string
+	Keywords         []string
+	EC               []string
+	GO               []string
+	PDB              []string
+}
+
+// Flatten projects the entry onto a FlatRecord.
+func (e Entry) Flatten() FlatRecord {
+	return FlatRecord{
+		Accession:        e.PrimaryAccession(),
+		EntryName:        e.EntryName(),
+		GeneName:         e.GeneName(),
+		ProteinName:      e.ProteinName(),
+		Organism:         e.ScientificName(),
+		TaxID:            e.TaxID(),
+		Length:           e.Sequence.Length,
+		Mass:             e.Sequence.Mass,
+		Reviewed:         e.IsReviewed(),
+		ProteinExistence: e.ProteinExistence.Type,
+		Keywords:         e.Keywords(),
+		EC:               e.ECNumbers(),
+		GO:               e.DbReferenceIDs("GO"),
+		PDB:              e.DbReferenceIDs("PDB"),
+	}
+}
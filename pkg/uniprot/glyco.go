@@ -0,0 +1,60 @@
+package uniprot
+
+import "strings"
+
+// GlycoSite is a glycosylated residue of an entry.
+type GlycoSite struct {
+	// Position is the modified residue, or -1 if unknown.
+	Position int
+	// Linkage is "N-linked", "O-linked", "C-linked" or "S-linked", or
+	// "unknown" when the description names none of them.
+	Linkage string
+	// Glycan is the parenthesized glycan of the description, e.g.
+	// "GlcNAc...", if any.
+	Glycan string
+	// Note is the full feature description.
+	Note string
+}
+
+// GlycosylationSites returns the entry's "glycosylation site" features with
+// the linkage type parsed from their descriptions, such as
+// "N-linked (GlcNAc...) asparagine" or "O-linked (GalNAc...) threonine".
+func (e Entry) GlycosylationSites() []GlycoSite {
+	var sites []GlycoSite
+	for _, f := range e.featuresOfType("glycosylation site") {
+		begin, _ := f.Location.span()
+		sites = append(sites, GlycoSite{
+			Position: knownPosition(begin),
+			Linkage:  glycoLinkage(f.Description),
+			Glycan:   glycan(f.Description),
+			Note:     f.Description,
+		})
+	}
+	return sites
+}
+
+// glycoLinkage returns the linkage type named at the start of desc.
+func glycoLinkage(desc string) string {
+	desc = strings.TrimSpace(desc)
+	if len(desc) < len("N-linked") || !strings.EqualFold(desc[1:len("N-linked")], "-linked") {
+		return "unknown"
+	}
+	switch c := strings.ToUpper(desc[:1]); c {
+	case "N", "O", "C", "S":
+		return c + "-linked"
+	}
+	return "unknown"
+}
+
+// glycan returns the text between the first pair of parentheses of desc.
+func glycan(desc string) string {
+	_, rest, ok := strings.Cut(desc, "(")
+	if !ok {
+		return ""
+	}
+	g, _, ok := strings.Cut(rest, ")")
+	if !ok {
+		return ""
+	}
+	return g
+}
@@ -0,0 +1,57 @@
+package uniprot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGlycosylationSites(t *testing.T) {
+	e := decodeEntry(t, `<entry>
+<accession>P02763</accession>
+<feature type="glycosylation site" description="N-linked (GlcNAc...) asparagine" evidence="1">
+  <location><position position="33"/></location>
+</feature>
+<feature type="domain" description="Lipocalin">
+  <location><begin position="20"/><end position="180"/></location>
+</feature>
+<feature type="glycosylation site" description="O-linked (GalNAc...) threonine">
+  <location><position position="56"/></location>
+</feature>
+<feature type="glycosylation site" description="N-linked (GlcNAc...) (complex) asparagine; partial">
+  <location><position position="72"/></location>
+</feature>
+<feature type="glycosylation site" description="C-linked (Man) tryptophan">
+  <location><position position="90"/></location>
+</feature>
+<feature type="glycosylation site" description="Glycosylated lysine">
+  <location><position status="unknown"/></location>
+</feature>
+</entry>`)
+	want := []GlycoSite{
+		{Position: 33, Linkage: "N-linked", Glycan: "GlcNAc...", Note: "N-linked (GlcNAc...) asparagine"},
+		{Position: 56, Linkage: "O-linked", Glycan: "GalNAc...", Note: "O-linked (GalNAc...) threonine"},
+		{Position: 72, Linkage: "N-linked", Glycan: "GlcNAc...", Note: "N-linked (GlcNAc...) (complex) asparagine; partial"},
+		{Position: 90, Linkage: "C-linked", Glycan: "Man", Note: "C-linked (Man) tryptophan"},
+		{Position: -1, Linkage: "unknown", Note: "Glycosylated lysine"},
+	}
+	got := e.GlycosylationSites()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GlycosylationSites() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestGlycoLinkage(t *testing.T) {
+	tests := map[string]string{
+		"N-linked (GlcNAc...) asparagine": "N-linked",
+		"o-linked (Xyl...) serine":        "O-linked",
+		"S-linked (Glc) cysteine":         "S-linked",
+		"X-linked":                        "unknown",
+		"N-":                              "unknown",
+		"":                                "unknown",
+	}
+	for desc, want := range tests {
+		if got := glycoLinkage(desc); got != want {
+			t.Errorf("glycoLinkage(%q) = %q, want %q", desc, got, want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package uniprot
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned by Index lookups for an accession that is not
+	// in the index.
+	ErrNotFound = errors.New("uniprot: accession not in index")
+	// ErrCompressedIndex is returned by BuildIndex for gzipped input, whose
+	// entries cannot be read at byte offsets.
+	ErrCompressedIndex = errors.New("uniprot: cannot index compressed file")
+)
+
+// Span is the byte range of an <entry>...</entry> element in a file, from
+// the opening "<" up to, but not including, End.
+type Span struct {
+	Begin, End int64
+}
+
+// Index maps the accessions of an uncompressed UniProt XML file to the
+// spans of their entries, so that single entries can be read without
+// scanning the file, either locally with Get or from a copy of the file on
+// an HTTP server with GetRemote.
+type Index struct {
+	filePath  string
+	spans     map[string]Span
+	normalize bool
+}
+
+// IndexOption configures BuildIndex and LoadIndex.
+type IndexOption func(*Index)
+
+// WithNormalizedAccessions makes the index compare accessions after
+// NormalizeAccession, on both the indexed and the queried side, so that
+// lowercase or padded accessions from user input still match. Lookups are
+// exact by default.
+func WithNormalizedAccessions() IndexOption {
+	return func(ix *Index) { ix.normalize = true }
+}
+
+func newIndex(filePath string, opts []IndexOption) *Index {
+	ix := &Index{filePath: filePath, spans: make(map[string]Span)}
+	for _, opt := range opts {
+		opt(ix)
+	}
+	return ix
+}
+
+// key returns the form in which acc is stored and looked up.
+func (ix *Index) key(acc string) string {
+	if ix.normalize {
+		return NormalizeAccession(acc)
+	}
+	return acc
+}
+
+// BuildIndex reads the uncompressed UniProt XML file at filePath once and
+// records the span of every entry under each of its accessions. A
+// secondary accession never shadows the primary accession of another
+// entry. Gzipped files are rejected with ErrCompressedIndex.
+func BuildIndex(filePath string, opts ...IndexOption) (*Index, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", filePath, err)
+	}
+	defer file.Close()
+	br := bufio.NewReader(file)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return nil, fmt.Errorf("%q: %w", filePath, ErrCompressedIndex)
+	}
+
+	ix := newIndex(filePath, opts)
+	primary := make(map[string]bool)
+	cfg := defaultConfig()
+	err = eachStart(&cfg, br, "entry", func(decoder *xml.Decoder, start xml.StartElement, offset int64) error {
+		entry, err := decodeEntryFields(decoder, start, FieldAccession)
+		if err != nil {
+			return err
+		}
+		span := Span{offset, decoder.InputOffset()}
+		for i, acc := range entry.Accession {
+			key := ix.key(acc)
+			if i == 0 {
+				primary[key] = true
+			} else if primary[key] {
+				continue
+			}
+			ix.spans[key] = span
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, decodeError(filePath, err)
+	}
+	return ix, nil
+}
+
+// Span returns the span of the entry with the given accession.
+func (ix *Index) Span(accession string) (Span, bool) {
+	span, ok := ix.spans[ix.key(accession)]
+	return span, ok
+}
+
+// Len returns the number of accessions in the index.
+func (ix *Index) Len() int {
+	return len(ix.spans)
+}
+
+// Get reads and decodes the entry with the given accession from the
+// indexed file. It returns an error wrapping ErrNotFound for an accession
+// that is not in the index.
+func (ix *Index) Get(accession string) (Entry, error) {
+	span, ok := ix.Span(accession)
+	if !ok {
+		return Entry{}, fmt.Errorf("%s: %w", accession, ErrNotFound)
+	}
+	file, err := os.Open(ix.filePath)
+	if err != nil {
+		return Entry{}, fmt.Errorf("opening %q: %w", ix.filePath, err)
+	}
+	defer file.Close()
+	data := make([]byte, span.End-span.Begin)
+	if _, err := file.ReadAt(data, span.Begin); err != nil {
+		return Entry{}, fmt.Errorf("reading %s from %q: %w", accession, ix.filePath, err)
+	}
+	return decodeSpan(data)
+}
+
+// GetRemote fetches the entry with the given accession from url, a copy of
+// the indexed file on an HTTP server or object store, with a single range
+// request for the entry's span, and decodes it. The server must honor the
+// Range header; a response with the whole file is rejected rather than
+// downloaded. Requests are made with http.DefaultClient under ctx.
+func (ix *Index) GetRemote(ctx context.Context, url, accession string) (Entry, error) {
+	span, ok := ix.Span(accession)
+	if !ok {
+		return Entry{}, fmt.Errorf("%s: %w", accession, ErrNotFound)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Entry{}, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", span.Begin, span.End-1))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return Entry{}, fmt.Errorf("fetching %s from %s: expected partial content, got %s", accession, url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, span.End-span.Begin))
+	if err != nil {
+		return Entry{}, fmt.Errorf("fetching %s from %s: %w", accession, url, err)
+	}
+	return decodeSpan(data)
+}
+
+// decodeSpan decodes the single entry element in data.
+func decodeSpan(data []byte) (Entry, error) {
+	var entry Entry
+	if err := xml.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("decoding entry: %w", err)
+	}
+	return entry, nil
+}
+
+// Save writes the index to w as tab-separated "accession\tbegin\tend"
+// lines in file order, for reuse with LoadIndex.
+func (ix *Index) Save(w io.Writer) error {
+	accs := slices.Collect(maps.Keys(ix.spans))
+	slices.SortFunc(accs, func(a, b string) int {
+		return cmp.Or(cmp.Compare(ix.spans[a].Begin, ix.spans[b].Begin), cmp.Compare(a, b))
+	})
+	bw := bufio.NewWriter(w)
+	for _, acc := range accs {
+		span := ix.spans[acc]
+		fmt.Fprintf(bw, "%s\t%d\t%d\n", acc, span.Begin, span.End)
+	}
+	return bw.Flush()
+}
+
+// LoadIndex reads an index written by Save for the file at filePath. The
+// file itself is not read; it is only needed later by Get.
+func LoadIndex(r io.Reader, filePath string, opts ...IndexOption) (*Index, error) {
+	ix := newIndex(filePath, opts)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("index line %d: expected 3 fields, got %d", n, len(fields))
+		}
+		begin, err1 := strconv.ParseInt(fields[1], 10, 64)
+		end, err2 := strconv.ParseInt(fields[2], 10, 64)
+		if err := errors.Join(err1, err2); err != nil || end < begin {
+			return nil, fmt.Errorf("index line %d: invalid span %s-%s", n, fields[1], fields[2])
+		}
+		ix.spans[ix.key(fields[0])] = Span{begin, end}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
@@ -0,0 +1,64 @@
+package uniprot
+
+import "strings"
+
+// ParentAccession returns the canonical accession of an isoform identifier,
+// e.g. "P12345" for "P12345-2". UniProt accessions never contain a hyphen,
+// so only a hyphen followed by digits is treated as an isoform suffix;
+// other identifiers are returned unchanged.
+func ParentAccession(isoformID string) string {
+	i := strings.LastIndexByte(isoformID, '-')
+	if i <= 0 || i == len(isoformID)-1 {
+		return isoformID
+	}
+	for _, c := range isoformID[i+1:] {
+		if c < '0' || c > '9' {
+			return isoformID
+		}
+	}
+	return isoformID[:i]
+}
+
+// IsoformAccessions returns the isoform identifiers, such as "P12345-2",
+// listed in the entry's alternative products comments.
+func (e Entry) IsoformAccessions() []string {
+	var ids []string
+	for _, c := range e.Comment {
+		for _, iso := range c.Isoform {
+			ids = append(ids, iso.ID...)
+		}
+	}
+	return ids
+}
+
+// IsoformFeatures maps the identifiers of the entry's described isoforms to
+// the "splice variant" features that turn the displayed sequence into
+// theirs. An isoform lists these features by ID in the ref attribute of its
+// sequence, as in <sequence type="described" ref="VSP_001 VSP_002"/>, and
+// the features are returned in that order. Isoforms whose sequence is the
+// displayed one or lies in another entry have no such features and are
+// left out, as are references to features the entry lacks.
+func (e Entry) IsoformFeatures() map[string][]Feature {
+	byID := make(map[string]Feature)
+	for _, f := range e.featuresOfType("splice variant") {
+		if f.Id != "" {
+			byID[f.Id] = f
+		}
+	}
+	isoforms := make(map[string][]Feature)
+	for _, c := range e.Comment {
+		for _, iso := range c.Isoform {
+			if iso.Sequence.Type != "described" || len(iso.ID) == 0 {
+				continue
+			}
+			var features []Feature
+			for _, ref := range strings.Fields(iso.Sequence.Ref) {
+				if f, ok := byID[ref]; ok {
+					features = append(features, f)
+				}
+			}
+			isoforms[iso.ID[0]] = features
+		}
+	}
+	return isoforms
+}
@@ -0,0 +1,192 @@
+package uniprot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Reduce folds f over the entries of src, starting from init. It stops and
+// returns the accumulated value along with the first error from src.
+func Reduce[T any](src iter.Seq2[Entry, error], init T, f func(T, Entry) T) (T, error) {
+	acc := init
+	for entry, err := range src {
+		if err != nil {
+			return acc, err
+		}
+		acc = f(acc, entry)
+	}
+	return acc, nil
+}
+
+// Batch groups the entries of src into slices of size entries; the last
+// batch may be shorter. Each batch is freshly allocated, so it may be
+// retained or handed to another goroutine. An error from src is yielded
+// after the pending entries, with a nil batch, and ends the iteration.
+// A size that is not positive yields a single error.
+func Batch(src iter.Seq2[Entry, error], size int) iter.Seq2[[]Entry, error] {
+	return func(yield func([]Entry, error) bool) {
+		if size <= 0 {
+			yield(nil, fmt.Errorf("invalid batch size: %d", size))
+			return
+		}
+		batch := make([]Entry, 0, size)
+		for entry, err := range src {
+			if err != nil {
+				if len(batch) > 0 && !yield(batch, nil) {
+					return
+				}
+				yield(nil, err)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) == size {
+				if !yield(batch, nil) {
+					return
+				}
+				batch = make([]Entry, 0, size)
+			}
+		}
+		if len(batch) > 0 {
+			yield(batch, nil)
+		}
+	}
+}
+
+// Tee ranges over src once and passes each entry to every consumer in turn.
+// Consumers run sequentially on the calling goroutine, in argument order,
+// and one entry reaches all of them before the next is read, so they may
+// share state that is not safe for concurrent use. Tee returns the first
+// error from src or from a consumer, which stops the iteration.
+func Tee(src iter.Seq2[Entry, error], consumers ...func(Entry) error) error {
+	for entry, err := range src {
+		if err != nil {
+			return err
+		}
+		for _, consume := range consumers {
+			if err := consume(entry); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// filterEntries yields the entries of src for which keep returns true,
+// passing errors through. Dropped entries are handed to dropped, if set.
+func filterEntries(src iter.Seq2[Entry, error], keep func(Entry) bool, dropped func(Entry)) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		for entry, err := range src {
+			if err == nil && !keep(entry) {
+				if dropped != nil {
+					dropped(entry)
+				}
+				continue
+			}
+			if !yield(entry, err) {
+				return
+			}
+		}
+	}
+}
+
+// HasSequence reports whether the entry carries a non-empty sequence.
+func (e Entry) HasSequence() bool {
+	return e.Sequence.Length > 0 && e.SequenceString() != ""
+}
+
+// FilterHasSequence returns a decorator dropping entries without a sequence,
+// such as deleted or demerged placeholders.
+func FilterHasSequence() func(iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	return FilterHasSequenceFunc(nil)
+}
+
+// FilterHasSequenceFunc is like FilterHasSequence but hands every dropped
+// entry to dropped, e.g. to log what was excluded.
+func FilterHasSequenceFunc(dropped func(Entry)) func(iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	return func(src iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+		return filterEntries(src, Entry.HasSequence, dropped)
+	}
+}
+
+// NormalizeAccession trims surrounding white space from acc and uppercases
+// it, the form in which UniProt writes accessions.
+func NormalizeAccession(acc string) string {
+	return strings.ToUpper(strings.TrimSpace(acc))
+}
+
+// IsObsolete reports whether the entry is a deleted or demerged stub
+// rather than an active UniProtKB entry. The only signal used is a dataset
+// attribute naming something other than "Swiss-Prot" or "TrEMBL", as in
+// deleted entries exported with a placeholder dataset. Missing metadata
+// is not taken for a deletion: entries without a dataset, version or
+// sequence, such as minimal documents, flat-file entries or projections
+// decoded by UniProtEntriesFields, count as active.
+func (e Entry) IsObsolete() bool {
+	return e.Dataset != "" && e.Dataset != "Swiss-Prot" && e.Dataset != "TrEMBL"
+}
+
+// FilterActive returns a decorator dropping obsolete entries, as reported
+// by IsObsolete.
+func FilterActive() func(iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	return func(src iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+		return filterEntries(src, func(e Entry) bool { return !e.IsObsolete() }, nil)
+	}
+}
+
+// FilterByAccession returns a decorator keeping the entries with any
+// accession, primary or secondary, in accessions. Matching is exact unless
+// normalize is set, in which case both sides go through NormalizeAccession
+// so that lowercase or padded IDs from external lists still match.
+func FilterByAccession(accessions []string, normalize bool) func(iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	norm := func(acc string) string { return acc }
+	if normalize {
+		norm = NormalizeAccession
+	}
+	wanted := make(map[string]bool, len(accessions))
+	for _, acc := range accessions {
+		wanted[norm(acc)] = true
+	}
+	return func(src iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+		return filterEntries(src, hasAccessionIn(wanted, norm), nil)
+	}
+}
+
+// hasAccessionIn returns a predicate holding for entries with an accession
+// that is in wanted after norm.
+func hasAccessionIn(wanted map[string]bool, norm func(string) string) func(Entry) bool {
+	return func(e Entry) bool {
+		for _, acc := range e.Accession {
+			if wanted[norm(acc)] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// FilterByAccessionReader keeps the entries of src with any accession in
+// the list read from accReader, one accession per line, such as a list
+// piped to standard input. Blank lines and text from "#" to the end of a
+// line are ignored, and accessions are compared after NormalizeAccession.
+// The list is read in full when iteration starts; an error reading it is
+// yielded and ends the iteration.
+func FilterByAccessionReader(accReader io.Reader, src iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		wanted := make(map[string]bool)
+		scanner := bufio.NewScanner(accReader)
+		for scanner.Scan() {
+			line, _, _ := strings.Cut(scanner.Text(), "#")
+			if acc := NormalizeAccession(line); acc != "" {
+				wanted[acc] = true
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			yield(Entry{}, fmt.Errorf("reading accession list: %w", err))
+			return
+		}
+		filterEntries(src, hasAccessionIn(wanted, NormalizeAccession), nil)(yield)
+	}
+}
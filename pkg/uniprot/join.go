@@ -0,0 +1,102 @@
+package uniprot
+
+import (
+	"iter"
+	"os"
+)
+
+// Pair is an entry of an old release matched with its counterpart in a new
+// one. Either side is nil for an entry found in only one release.
+type Pair struct {
+	Old, New *Entry
+}
+
+// JoinByAccession returns an iterator over the entries of two releases
+// matched by accession, for release-to-release comparison with Diff. The
+// smaller file is held in memory, indexed by all its accessions, and the
+// larger one is streamed; the pairs come in the order of the larger file,
+// followed by the unmatched entries of the smaller one in their order.
+//
+// Entries match when they share their primary accession or, failing that,
+// when the accession of one appears among those of the other, which follows
+// entries across merges. Each entry is matched at most once, so when an
+// entry was split, the parts beyond the first are yielded with a nil
+// counterpart. Errors opening or decoding either file are yielded and end
+// the iteration.
+func JoinByAccession(oldPath, newPath string) iter.Seq2[Pair, error] {
+	return func(yield func(Pair, error) bool) {
+		small, large := oldPath, newPath
+		swapped := false
+		if larger, err := isLarger(oldPath, newPath); err != nil {
+			yield(Pair{}, err)
+			return
+		} else if larger {
+			small, large = newPath, oldPath
+			swapped = true
+		}
+		pair := func(indexed, streamed *Entry) Pair {
+			if swapped {
+				return Pair{Old: streamed, New: indexed}
+			}
+			return Pair{Old: indexed, New: streamed}
+		}
+
+		var entries []Entry
+		for entry, err := range UniProtEntries(small) {
+			if err != nil {
+				yield(Pair{}, err)
+				return
+			}
+			entries = append(entries, entry)
+		}
+		// Primary accessions take precedence over secondary ones.
+		index := make(map[string]int)
+		for i, entry := range entries {
+			for _, acc := range entry.Accession[min(1, len(entry.Accession)):] {
+				index[acc] = i
+			}
+		}
+		for i, entry := range entries {
+			if acc := entry.PrimaryAccession(); acc != "" {
+				index[acc] = i
+			}
+		}
+		matched := make([]bool, len(entries))
+
+		for entry, err := range UniProtEntries(large) {
+			if err != nil {
+				yield(Pair{}, err)
+				return
+			}
+			var indexed *Entry
+			for _, acc := range entry.Accession {
+				if i, ok := index[acc]; ok && !matched[i] {
+					matched[i] = true
+					indexed = &entries[i]
+					break
+				}
+			}
+			if !yield(pair(indexed, &entry), nil) {
+				return
+			}
+		}
+		for i := range entries {
+			if !matched[i] && !yield(pair(&entries[i], nil), nil) {
+				return
+			}
+		}
+	}
+}
+
+// isLarger reports whether the file at a is larger than the one at b.
+func isLarger(a, b string) (bool, error) {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return ia.Size() > ib.Size(), nil
+}
@@ -0,0 +1,49 @@
+package uniprot
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"iter"
+)
+
+// WriteJSONL writes each entry as one JSON object per line. Entries are
+// written as they are unless WithSortedDbReferences is given.
+func WriteJSONL(w io.Writer, entries iter.Seq2[Entry, error], opts ...WriteOption) error {
+	cfg := newWriteConfig(opts)
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		if err := enc.Encode(cfg.prepare(entry)); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// EntriesFromJSONL returns an iterator over the entries in newline-delimited
+// JSON as written by WriteJSONL. Every field of the Entry model survives the
+// round trip. A malformed object is yielded as an error and ends the
+// iteration.
+func EntriesFromJSONL(r io.Reader) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		dec := json.NewDecoder(r)
+		for {
+			var entry Entry
+			err := dec.Decode(&entry)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(Entry{}, err)
+				return
+			}
+			if !yield(entry, nil) {
+				return
+			}
+		}
+	}
+}
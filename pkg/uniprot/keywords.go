@@ -0,0 +1,122 @@
+package uniprot
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"sync"
+)
+
+// keywordCategories maps keyword names and accessions (KW-xxxx) to their
+// category. It starts out with a small built-in set of common keywords and
+// can be completed with LoadKeywordCategories.
+var (
+	keywordMu         sync.RWMutex
+	keywordCategories = map[string]string{
+		"3D-structure":              "Technical term",
+		"Reference proteome":        "Technical term",
+		"Direct protein sequencing": "Technical term",
+		"Alternative splicing":      "Coding sequence diversity",
+		"Polymorphism":              "Coding sequence diversity",
+		"Acetylation":               "PTM",
+		"Disulfide bond":            "PTM",
+		"Glycoprotein":              "PTM",
+		"Phosphoprotein":            "PTM",
+		"Ubl conjugation":           "PTM",
+		"ATP-binding":               "Ligand",
+		"Metal-binding":             "Ligand",
+		"Nucleotide-binding":        "Ligand",
+		"Zinc":                      "Ligand",
+		"DNA-binding":               "Molecular function",
+		"Hydrolase":                 "Molecular function",
+		"Kinase":                    "Molecular function",
+		"Oxidoreductase":            "Molecular function",
+		"Transferase":               "Molecular function",
+		"Apoptosis":                 "Biological process",
+		"Transcription":             "Biological process",
+		"Transport":                 "Biological process",
+		"Cytoplasm":                 "Cellular component",
+		"Membrane":                  "Cellular component",
+		"Mitochondrion":             "Cellular component",
+		"Nucleus":                   "Cellular component",
+		"Secreted":                  "Cellular component",
+		"Coiled coil":               "Domain",
+		"Repeat":                    "Domain",
+		"Signal":                    "Domain",
+		"Transmembrane":             "Domain",
+		"Transmembrane helix":       "Domain",
+		"Disease variant":           "Disease",
+	}
+)
+
+// LoadKeywordCategories reads the UniProt keyword vocabulary in the
+// keywlist.txt format and registers the category of every keyword, by name
+// and by accession, replacing the built-in defaults where they overlap.
+func LoadKeywordCategories(r io.Reader) error {
+	loaded := make(map[string]string)
+	var name, acc, category string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "//" {
+			if category != "" {
+				if name != "" {
+					loaded[name] = category
+				}
+				if acc != "" {
+					loaded[acc] = category
+				}
+			}
+			name, acc, category = "", "", ""
+			continue
+		}
+		if len(line) < 5 {
+			continue
+		}
+		value := strings.TrimSuffix(strings.TrimSpace(line[5:]), ".")
+		switch line[:2] {
+		case "ID":
+			name = value
+		case "AC":
+			acc = value
+		case "CA":
+			category = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	keywordMu.Lock()
+	defer keywordMu.Unlock()
+	for k, v := range loaded {
+		keywordCategories[k] = v
+	}
+	return nil
+}
+
+// KeywordCategory returns the category of a keyword given by name or by
+// accession, and whether it is known.
+func KeywordCategory(keyword string) (string, bool) {
+	keywordMu.RLock()
+	defer keywordMu.RUnlock()
+	category, ok := keywordCategories[keyword]
+	return category, ok
+}
+
+// KeywordsByCategory groups the entry's keywords by category, such as
+// "Molecular function" or "PTM". Keywords of unknown category, which are
+// frequent unless LoadKeywordCategories has been called, are left out.
+func (e Entry) KeywordsByCategory() map[string][]string {
+	groups := make(map[string][]string)
+	for _, k := range e.Keyword {
+		category, ok := KeywordCategory(k.ID)
+		if !ok {
+			category, ok = KeywordCategory(k.Value)
+		}
+		if ok {
+			groups[category] = append(groups[category], k.Value)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,33 @@
+package uniprot
+
+// Base URLs of the UniProt website and REST API. The URL helpers build on
+// these, so a change of URL scheme needs updating only here.
+const (
+	WebBaseURL  = "https://www.uniprot.org/uniprotkb/"
+	RESTBaseURL = "https://rest.uniprot.org/uniprotkb/"
+)
+
+// WebURL returns the address of the entry's page on the UniProt website,
+// or "" if the entry has no accession.
+func (e Entry) WebURL() string {
+	return entryURL(WebBaseURL, e.PrimaryAccession(), "/entry")
+}
+
+// FASTAURL returns the REST API address of the entry's sequence in FASTA
+// format, or "" if the entry has no accession.
+func (e Entry) FASTAURL() string {
+	return entryURL(RESTBaseURL, e.PrimaryAccession(), ".fasta")
+}
+
+// XMLURL returns the REST API address of the entry in UniProt XML, or "" if
+// the entry has no accession.
+func (e Entry) XMLURL() string {
+	return entryURL(RESTBaseURL, e.PrimaryAccession(), ".xml")
+}
+
+func entryURL(base, accession, suffix string) string {
+	if accession == "" {
+		return ""
+	}
+	return base + accession + suffix
+}
@@ -0,0 +1,35 @@
+package uniprot
+
+// MassSpec is an experimentally determined molecular mass reported in a
+// "mass spectrometry" comment.
+type MassSpec struct {
+	Mass     float64
+	Error    string
+	Method   string
+	Molecule string
+	Location Location
+}
+
+// MassSpectrometry returns the masses reported in the entry's "mass
+// spectrometry" comments. Method names the technique, e.g. "MALDI" or
+// "Electrospray", and Molecule the isoform or chain measured, if given.
+func (e Entry) MassSpectrometry() []MassSpec {
+	var specs []MassSpec
+	for _, c := range e.Comment {
+		if c.Type != "mass spectrometry" {
+			continue
+		}
+		molecule := c.Molecule.Value
+		if molecule == "" {
+			molecule = c.Molecule.ID
+		}
+		specs = append(specs, MassSpec{
+			Mass:     c.Mass,
+			Error:    c.MassError,
+			Method:   c.Method,
+			Molecule: molecule,
+			Location: c.Location,
+		})
+	}
+	return specs
+}
@@ -0,0 +1,40 @@
+package uniprot
+
+import "slices"
+
+// Merge layers the keywords, features and cross-references of overlay that
+// base lacks on top of base. Duplicates are detected as in Diff: keywords by
+// value, features by feature ID or else by type and location, and
+// cross-references by database and ID. Everything else is taken from base.
+// The sequence is taken from overlay only when both checksums match, which
+// picks up a newer sequence version and modification date.
+//
+// Feature coordinates are merged as they are. If the two sequences differ,
+// the features added from overlay refer to the overlay sequence.
+func Merge(base, overlay Entry) Entry {
+	merged := base
+	merged.Keyword = mergeUnique(base.Keyword, overlay.Keyword, func(k Keyword) string { return k.Value })
+	merged.Feature = mergeUnique(base.Feature, overlay.Feature, featureKey)
+	merged.DbReference = mergeUnique(base.DbReference, overlay.DbReference, dbReferenceKey)
+	if base.Sequence.Checksum != "" && base.Sequence.Checksum == overlay.Sequence.Checksum {
+		merged.Sequence = overlay.Sequence
+	}
+	return merged
+}
+
+// mergeUnique returns a copy of base followed by the elements of overlay
+// whose key is not yet present, preserving the order of both.
+func mergeUnique[T any](base, overlay []T, key func(T) string) []T {
+	merged := slices.Clone(base)
+	seen := make(map[string]bool, len(base))
+	for _, v := range base {
+		seen[key(v)] = true
+	}
+	for _, v := range overlay {
+		if k := key(v); !seen[k] {
+			seen[k] = true
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
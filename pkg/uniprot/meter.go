@@ -0,0 +1,66 @@
+package uniprot
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"sync/atomic"
+)
+
+// Meter tracks the progress of an iteration started with
+// UniProtEntriesMetered. Its methods may be called from other goroutines,
+// e.g. to drive a progress bar.
+type Meter struct {
+	bytesRead atomic.Int64
+	entries   atomic.Int64
+}
+
+// BytesRead returns the number of bytes read from the file so far. For a
+// gzipped file these are compressed bytes, so BytesRead divided by the file
+// size gives the fraction done.
+func (m *Meter) BytesRead() int64 {
+	return m.bytesRead.Load()
+}
+
+// Entries returns the number of entries yielded so far.
+func (m *Meter) Entries() int64 {
+	return m.entries.Load()
+}
+
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// UniProtEntriesMetered is like UniProtEntries but also returns a Meter
+// reporting the bytes read from the file, before decompression, and the
+// entries yielded.
+func UniProtEntriesMetered(filePath string) (iter.Seq2[Entry, error], *Meter) {
+	m := &Meter{}
+	return func(yield func(Entry, error) bool) {
+		file, err := os.Open(filePath)
+		if err != nil {
+			yield(Entry{}, fmt.Errorf("opening %q: %w", filePath, err))
+			return
+		}
+		defer file.Close()
+
+		for entry, err := range UniProtEntriesReader(countingReader{file, &m.bytesRead}) {
+			if err == nil {
+				m.entries.Add(1)
+			} else {
+				err = decodeError(filePath, err)
+			}
+			if !yield(entry, err) {
+				return
+			}
+		}
+	}, m
+}
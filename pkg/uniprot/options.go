@@ -0,0 +1,153 @@
+package uniprot
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrDTD is returned when a document type declaration is found in input
+// decoded with WithoutDTD.
+var ErrDTD = errors.New("uniprot: document type declaration not allowed")
+
+// ErrEntryTooLarge is yielded for an entry that exceeds the size set with
+// WithMaxEntrySize. The entry is skipped and iteration goes on with the next
+// one.
+var ErrEntryTooLarge = errors.New("uniprot: entry too large")
+
+// Option configures how UniProtEntriesWith decodes its input.
+type Option func(*config)
+
+type config struct {
+	strict     bool
+	entity     map[string]string
+	noDTD      bool
+	bufferSize int
+	// inputSize is the length of input known up front, such as an
+	// in-memory document, or 0; the read buffer is never made larger.
+	inputSize int64
+	// maxEntrySize is the cap on the input bytes of one element; 0 means
+	// unlimited.
+	maxEntrySize int64
+}
+
+// DefaultBufferSize is the default size of the read buffer in front of the
+// XML decoder. Decoding is CPU-bound, and BenchmarkBufferSize shows no gain
+// from larger buffers, so the default stays small; every decoder,
+// including those of tar members and in-memory documents, allocates one.
+const DefaultBufferSize = 64 << 10
+
+func defaultConfig() config {
+	return config{strict: true, bufferSize: DefaultBufferSize}
+}
+
+func newConfig(opts []Option) config {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithStrict sets the Strict mode of the XML decoder (true by default).
+func WithStrict(strict bool) Option {
+	return func(c *config) { c.strict = strict }
+}
+
+// WithEntities sets additional entity definitions for the XML decoder.
+func WithEntities(entity map[string]string) Option {
+	return func(c *config) { c.entity = entity }
+}
+
+// WithoutDTD locks the decoder down for untrusted input: the document must
+// not contain a document type declaration, only the predefined XML entities
+// are recognized, and the decoder runs in strict mode.
+func WithoutDTD() Option {
+	return func(c *config) {
+		c.noDTD = true
+		c.entity = nil
+		c.strict = true
+	}
+}
+
+// WithBufferSize sets the size of the buffer through which the decompressed
+// input is read (DefaultBufferSize by default). Larger buffers may reduce
+// reads on slow network or spinning storage. The buffer is never larger
+// than an input whose length is known, such as that of UniProtEntriesBytes.
+func WithBufferSize(size int) Option {
+	return func(c *config) { c.bufferSize = size }
+}
+
+// WithMaxEntrySize caps the input consumed by a single entry at size bytes
+// of decompressed XML, guarding against crafted input that would exhaust
+// memory. An entry over the cap yields an error wrapping ErrEntryTooLarge
+// and is skipped without being kept in memory; iteration then resumes with
+// the next entry. The check is made between XML tokens, so one huge text
+// node is still read in full by the decoder before it is rejected.
+//
+// The default is 0, meaning unlimited. The largest entries of current
+// UniProtKB releases stay below a few megabytes, so 16 MiB is a safe cap
+// for untrusted input; combine it with WithoutDTD.
+func WithMaxEntrySize(size int64) Option {
+	return func(c *config) { c.maxEntrySize = size }
+}
+
+func (c *config) newDecoder(r io.Reader) *xml.Decoder {
+	size := c.bufferSize
+	if c.inputSize > 0 {
+		size = int(min(int64(size), c.inputSize))
+	}
+	decoder := xml.NewDecoder(bufio.NewReaderSize(r, size))
+	decoder.Strict = c.strict
+	decoder.Entity = c.entity
+	decoder.CharsetReader = charsetReader
+	return decoder
+}
+
+// charsetReader converts the ISO-8859-1 input declared by UniRef files to
+// UTF-8. ASCII is a subset of UTF-8 and passes through unchanged.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		return &latin1Reader{r: bufio.NewReader(input)}, nil
+	case "us-ascii", "ascii":
+		return input, nil
+	}
+	return nil, fmt.Errorf("unsupported charset: %s", charset)
+}
+
+// latin1Reader decodes ISO-8859-1, where every byte is a code point.
+type latin1Reader struct {
+	r   *bufio.Reader
+	buf []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(l.buf) > 0 {
+			c := copy(p[n:], l.buf)
+			l.buf = l.buf[c:]
+			n += c
+			continue
+		}
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+		l.buf = utf8.AppendRune(l.buf[:0], rune(b))
+	}
+	return n, nil
+}
@@ -0,0 +1,59 @@
+package uniprot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// allocated returns the bytes allocated while running f.
+func allocated(f func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func TestReadBufferFitsInput(t *testing.T) {
+	doc := wrapEntries(`<entry><accession>P1</accession></entry>`)
+	// Even with a large buffer requested, a small document must not
+	// allocate one.
+	n := allocated(func() {
+		for _, err := range UniProtEntriesString(doc, WithBufferSize(16<<20)) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if n > 64<<10 {
+		t.Errorf("decoding a %d-byte document allocated %d bytes", len(doc), n)
+	}
+}
+
+func BenchmarkBufferSize(b *testing.B) {
+	data, err := os.ReadFile(benchmarkFile(b))
+	if err != nil {
+		b.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(data)
+	if err := zw.Close(); err != nil {
+		b.Fatal(err)
+	}
+	path := writeTemp(b, "bench.xml.gz", buf.Bytes())
+
+	for _, size := range []int{4 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20} {
+		b.Run(fmt.Sprintf("%dKiB", size>>10), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for b.Loop() {
+				drain(b, UniProtEntriesWith(path, WithBufferSize(size)))
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package uniprot
+
+import "iter"
+
+// FilterFunc returns a decorator keeping the entries for which pred returns
+// true. Errors from the source pass through untouched. Methods of Entry
+// serve as predicates through method expressions, e.g.
+//
+//	FilterFunc(And(Entry.IsReviewed, HasKeyword("Kinase")))
+func FilterFunc(pred func(Entry) bool) func(iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+	return func(src iter.Seq2[Entry, error]) iter.Seq2[Entry, error] {
+		return filterEntries(src, pred, nil)
+	}
+}
+
+// Query returns an iterator over the entries of filePath for which all
+// preds hold. The predicates are checked in order within a single loop and
+// stop at the first that fails, avoiding the per-entry overhead of stacking
+// one FilterFunc decorator per predicate. Errors are yielded as by
+// UniProtEntries.
+func Query(filePath string, preds ...func(Entry) bool) iter.Seq2[Entry, error] {
+	return filterEntries(UniProtEntries(filePath), And(preds...), nil)
+}
+
+// And returns a predicate that holds when all preds hold. It stops at the
+// first one that fails.
+func And(preds ...func(Entry) bool) func(Entry) bool {
+	return func(e Entry) bool {
+		for _, pred := range preds {
+			if !pred(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a predicate that holds when any of preds holds. It stops at the
+// first one that succeeds.
+func Or(preds ...func(Entry) bool) func(Entry) bool {
+	return func(e Entry) bool {
+		for _, pred := range preds {
+			if pred(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns the negation of pred.
+func Not(pred func(Entry) bool) func(Entry) bool {
+	return func(e Entry) bool {
+		return !pred(e)
+	}
+}
+
+// HasKeyword returns a predicate holding for entries with the given keyword.
+func HasKeyword(keyword string) func(Entry) bool {
+	return func(e Entry) bool {
+		for _, k := range e.Keyword {
+			if k.Value == keyword {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,88 @@
+package uniprot
+
+import (
+	"iter"
+	"slices"
+	"testing"
+)
+
+// benchmarkPredicates all hold for the benchmark entries, so that every
+// predicate runs on every entry.
+var benchmarkPredicates = []func(Entry) bool{
+	Entry.IsReviewed,
+	Entry.HasSequence,
+	Not(Entry.IsObsolete),
+	HasKeyword("Phosphoprotein"),
+	Or(HasKeyword("Kinase"), HasKeyword("3D-structure")),
+}
+
+// chain stacks one FilterFunc decorator per predicate.
+func chain(src iter.Seq2[Entry, error], preds ...func(Entry) bool) iter.Seq2[Entry, error] {
+	for _, pred := range preds {
+		src = FilterFunc(pred)(src)
+	}
+	return src
+}
+
+func TestQuery(t *testing.T) {
+	accessions := func(entries []Entry) []string {
+		return mapSlice(entries, Entry.PrimaryAccession)
+	}
+	tests := []struct {
+		preds []func(Entry) bool
+		want  []string
+	}{
+		{nil, []string{"P69905", "Q00001"}},
+		{[]func(Entry) bool{Entry.IsReviewed}, []string{"P69905"}},
+		{[]func(Entry) bool{Not(Entry.IsReviewed)}, []string{"Q00001"}},
+		{benchmarkPredicates, []string{"P69905"}},
+		{[]func(Entry) bool{Entry.IsReviewed, HasKeyword("Kinase")}, nil},
+	}
+	for i, tt := range tests {
+		got := accessions(readAll(t, Query(sampleFile, tt.preds...)))
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("case %d: Query() = %v, want %v", i, got, tt.want)
+		}
+		chained := accessions(readAll(t, chain(UniProtEntries(sampleFile), tt.preds...)))
+		if !slices.Equal(chained, got) {
+			t.Errorf("case %d: chained filters = %v, Query() = %v", i, chained, got)
+		}
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	path := benchmarkFile(b)
+	for b.Loop() {
+		drain(b, Query(path, benchmarkPredicates...))
+	}
+}
+
+func BenchmarkChainedFilters(b *testing.B) {
+	path := benchmarkFile(b)
+	for b.Loop() {
+		drain(b, chain(UniProtEntries(path), benchmarkPredicates...))
+	}
+}
+
+func TestFilterActive(t *testing.T) {
+	doc := wrapEntries(
+		minimalEntry,
+		`<entry dataset="Deleted"><accession>P2</accession></entry>`,
+		`<entry dataset="TrEMBL" version="3"><accession>P3</accession><sequence length="2">MK</sequence></entry>`,
+	)
+	path := gzipTemp(t, "active.xml.gz", []byte(doc))
+	// Missing metadata does not make an entry obsolete, so a projection
+	// without the sequence keeps the same entries.
+	for name, src := range map[string]iter.Seq2[Entry, error]{
+		"full":       UniProtEntries(path),
+		"projection": UniProtEntriesFields(path, FieldAccession),
+	} {
+		var got []string
+		for _, e := range readAll(t, FilterActive()(src)) {
+			got = append(got, e.PrimaryAccession())
+		}
+		if want := []string{"P1", "P3"}; !slices.Equal(got, want) {
+			t.Errorf("%s: active entries %v, want %v", name, got, want)
+		}
+	}
+}
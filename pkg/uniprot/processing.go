@@ -0,0 +1,101 @@
+package uniprot
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// MatureSequence returns the sequence left after removal of the signal
+// peptide and of any propeptides immediately following it, and true. If no
+// signal peptide with a known end is annotated, it returns the full
+// sequence and false.
+func (e Entry) MatureSequence() (string, bool) {
+	seq := e.SequenceString()
+	cut, found := 0, false
+	for _, f := range e.featuresOfType("signal peptide") {
+		if _, end, ok := f.Location.bounds(); ok {
+			cut, found = max(cut, end), true
+		}
+	}
+	if !found {
+		return seq, false
+	}
+
+	propeptides := e.featuresOfType("propeptide")
+	for extended := true; extended; {
+		extended = false
+		for _, f := range propeptides {
+			if begin, end, ok := f.Location.bounds(); ok && begin == cut+1 {
+				cut, extended = end, true
+			}
+		}
+	}
+	if cut >= len(seq) {
+		return "", true
+	}
+	return seq[cut:], true
+}
+
+// IsPrecursor reports whether the sequence is that of a precursor, which
+// is processed into the mature protein. The authoritative signal is the
+// precursor attribute of the sequence element; the protein element carries
+// no such flag in the current schema. A signal peptide or propeptide alone
+// does not make IsPrecursor true.
+func (e Entry) IsPrecursor() bool {
+	return e.Sequence.Precursor
+}
+
+// IsFragment reports whether the sequence is incomplete, as given by the
+// fragment attribute of the sequence element ("single" or "multiple").
+func (e Entry) IsFragment() bool {
+	return e.Sequence.Fragment != ""
+}
+
+// processingTypes are the feature types describing the maturation of a
+// precursor: the removed peptides and the products left after cleavage.
+var processingTypes = map[string]bool{
+	"signal peptide":  true,
+	"transit peptide": true,
+	"propeptide":      true,
+	"chain":           true,
+	"peptide":         true,
+}
+
+// ProcessingFeatures returns the signal peptide, transit peptide and
+// propeptide features of the entry together with the mature chains and
+// peptides they leave, in N- to C-terminal order. Features starting at the
+// same position are ordered by end, and features with an unknown start come
+// last.
+func (e Entry) ProcessingFeatures() []Feature {
+	var features []Feature
+	for _, f := range e.Feature {
+		if processingTypes[f.Type] {
+			features = append(features, f)
+		}
+	}
+	key := func(f Feature) (int, int) {
+		begin, end := f.Location.span()
+		if begin <= 0 {
+			begin = math.MaxInt
+		}
+		return begin, end
+	}
+	slices.SortStableFunc(features, func(a, b Feature) int {
+		aBegin, aEnd := key(a)
+		bBegin, bEnd := key(b)
+		return cmp.Or(cmp.Compare(aBegin, bBegin), cmp.Compare(aEnd, bEnd))
+	})
+	return features
+}
+
+// TransitDestination returns the organelle a transit peptide targets, as
+// named by its description, e.g. "Chloroplast" or "Mitochondrion". It
+// returns "" for other features or when no destination is described.
+func (f Feature) TransitDestination() string {
+	if f.Type != "transit peptide" {
+		return ""
+	}
+	return strings.TrimSpace(f.Description)
+}
@@ -0,0 +1,49 @@
+package uniprot
+
+import "strings"
+
+// modificationTypes are the feature types describing covalent
+// modifications of residues.
+var modificationTypes = map[string]bool{
+	"modified residue":            true,
+	"lipid moiety-binding region": true,
+	"glycosylation site":          true,
+	"disulfide bond":              true,
+	"cross-link":                  true,
+}
+
+// PTMDescription returns the text of the entry's "PTM" comments joined with
+// spaces, or "" if it has none.
+func (e Entry) PTMDescription() string {
+	return strings.Join(e.CommentText("PTM"), " ")
+}
+
+// ModifiedResidues returns the features describing covalent modifications:
+// modified residues, lipidation, glycosylation, disulfide bonds and
+// cross-links, in the order of the entry.
+func (e Entry) ModifiedResidues() []Feature {
+	var features []Feature
+	for _, f := range e.Feature {
+		if modificationTypes[f.Type] {
+			features = append(features, f)
+		}
+	}
+	return features
+}
+
+// PTMSummary pairs the prose of an entry's PTM comments with its
+// residue-level modification features.
+type PTMSummary struct {
+	Description string
+	Residues    []Feature
+}
+
+// PTMs returns the PTM description and modification features of the entry
+// together. Either part may be empty, as the comment and the features are
+// curated independently.
+func (e Entry) PTMs() PTMSummary {
+	return PTMSummary{
+		Description: e.PTMDescription(),
+		Residues:    e.ModifiedResidues(),
+	}
+}
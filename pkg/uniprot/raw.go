@@ -0,0 +1,125 @@
+package uniprot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"iter"
+)
+
+// RawEntry is a decoded entry together with the exact XML it was decoded from.
+type RawEntry struct {
+	Entry Entry
+	Raw   []byte
+}
+
+// UniProtEntriesRaw is like UniProtEntries but also yields the bytes of each
+// <entry>...</entry> element as found in the (decompressed) input, for
+// lossless re-export or for inspecting content the Entry model drops. Only
+// the bytes of the current entry are buffered.
+func UniProtEntriesRaw(filePath string) iter.Seq2[RawEntry, error] {
+	return func(yield func(RawEntry, error) bool) {
+		r, err := Open(filePath)
+		if err != nil {
+			yield(RawEntry{}, err)
+			return
+		}
+		defer r.Close()
+
+		rec := &recorder{r: r}
+		cfg := defaultConfig()
+		err = eachStart(&cfg, rec, "entry", func(decoder *xml.Decoder, start xml.StartElement, offset int64) error {
+			var entry Entry
+			if err := decoder.DecodeElement(&entry, &start); err != nil {
+				return err
+			}
+			end := decoder.InputOffset()
+			raw := bytes.Clone(rec.slice(offset, end))
+			rec.discard(end)
+			if !yield(RawEntry{entry, raw}, nil) {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			yield(RawEntry{}, decodeError(filePath, err))
+		}
+	}
+}
+
+// Located is a decoded entry together with its position in the input.
+type Located struct {
+	Entry Entry
+	// Offset is the byte offset of the entry's opening "<" in the
+	// decompressed input.
+	Offset int64
+	// Line is the 1-based line of the entry's start tag.
+	Line int
+}
+
+// UniProtEntriesLocated is like UniProtEntriesWith but also yields the
+// position at which each entry starts, for pointing users at the source of
+// an entry that fails validation. An entry over WithMaxEntrySize is yielded
+// with its position and an error wrapping ErrEntryTooLarge. Offsets refer
+// to the decompressed XML, so for gzipped files they are best reported
+// together with the line.
+func UniProtEntriesLocated(filePath string, opts ...Option) iter.Seq2[Located, error] {
+	cfg := newConfig(opts)
+	return func(yield func(Located, error) bool) {
+		r, err := Open(filePath)
+		if err != nil {
+			yield(Located{}, err)
+			return
+		}
+		defer r.Close()
+
+		err = eachStart(&cfg, r, "entry", func(decoder *xml.Decoder, start xml.StartElement, offset int64) error {
+			line, _ := decoder.InputPos()
+			var entry Entry
+			var err error
+			if cfg.maxEntrySize > 0 {
+				err = decodeBounded(decoder, start, offset, cfg.maxEntrySize, &entry)
+			} else {
+				err = decoder.DecodeElement(&entry, &start)
+			}
+			if errors.Is(err, ErrEntryTooLarge) {
+				err = decodeError(filePath, err)
+			} else if err != nil {
+				return err
+			}
+			if !yield(Located{entry, offset, line}, err) {
+				return errStop
+			}
+			return nil
+		})
+		if err != nil && err != errStop {
+			yield(Located{}, decodeError(filePath, err))
+		}
+	}
+}
+
+// recorder keeps the bytes read through it from offset base onwards.
+type recorder struct {
+	r    io.Reader
+	buf  []byte
+	base int64
+}
+
+func (rec *recorder) Read(p []byte) (int, error) {
+	n, err := rec.r.Read(p)
+	rec.buf = append(rec.buf, p[:n]...)
+	return n, err
+}
+
+// slice returns the recorded bytes between the offsets from and to.
+func (rec *recorder) slice(from, to int64) []byte {
+	return rec.buf[from-rec.base : to-rec.base]
+}
+
+// discard forgets the recorded bytes before offset off.
+func (rec *recorder) discard(off int64) {
+	n := copy(rec.buf, rec.buf[off-rec.base:])
+	rec.buf = rec.buf[:n]
+	rec.base = off
+}
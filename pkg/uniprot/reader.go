@@ -0,0 +1,232 @@
+package uniprot
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"strings"
+)
+
+// errStop is returned by eachElement when the callback asks to stop.
+var errStop = errors.New("uniprot: iteration stopped")
+
+// decompress returns a reader over the decompressed contents of r if r starts
+// with the gzip magic number, or over r itself otherwise. The gzip reader is
+// left in multistream mode, so concatenated members (cat a.gz b.gz) read as
+// one continuous stream even when a boundary falls inside an entry.
+func decompress(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Open opens filePath for reading. Gzip-compressed files are detected by
+// their magic number and decompressed transparently, including files made
+// of several concatenated gzip members. Errors are wrapped with the path,
+// so errors.Is(err, os.ErrNotExist) tells a missing file from other
+// failures.
+func Open(filePath string) (io.ReadCloser, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", filePath, err)
+	}
+	r, err := decompress(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("reading gzip header of %q: %w", filePath, err)
+	}
+	return readCloser{r, file}, nil
+}
+
+// decodeError wraps an error met while reading or decoding filePath.
+func decodeError(filePath string, err error) error {
+	return fmt.Errorf("decoding %q: %w", filePath, err)
+}
+
+// Elements returns an iterator over the elements called name in an XML file,
+// optionally gzipped, each decoded into a T. It is the streaming core shared
+// by UniProtEntriesWith and the readers for related formats. Errors opening
+// or decoding the file are yielded and end the iteration.
+func Elements[T any](filePath, name string, opts ...Option) iter.Seq2[T, error] {
+	cfg := newConfig(opts)
+	return func(yield func(T, error) bool) {
+		var zero T
+		r, err := Open(filePath)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+		defer r.Close()
+
+		err = eachElement(&cfg, r, name, func(v T, err error) bool {
+			if err != nil {
+				err = decodeError(filePath, err)
+			}
+			return yield(v, err)
+		})
+		if err != nil && err != errStop {
+			yield(zero, decodeError(filePath, err))
+		}
+	}
+}
+
+// UniProtEntriesWith returns an iterator over UniProt entries from an XML
+// file, optionally gzipped, decoded according to opts. Errors opening or
+// decoding the file are yielded and end the iteration.
+func UniProtEntriesWith(filePath string, opts ...Option) iter.Seq2[Entry, error] {
+	return Elements[Entry](filePath, "entry", opts...)
+}
+
+// UniProtEntriesReader returns an iterator over the UniProt entries read
+// from r, which may be gzipped. The iterator consumes r, so it can only be
+// ranged over once.
+func UniProtEntriesReader(r io.Reader, opts ...Option) iter.Seq2[Entry, error] {
+	return entriesReader(r, newConfig(opts))
+}
+
+// entriesReader is UniProtEntriesReader with a prepared configuration.
+func entriesReader(r io.Reader, cfg config) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		dr, err := decompress(r)
+		if err == nil {
+			err = eachElement(&cfg, dr, "entry", yield)
+		}
+		if err != nil && err != errStop {
+			yield(Entry{}, err)
+		}
+	}
+}
+
+// UniProtEntriesBytes returns an iterator over the UniProt entries in data,
+// which may be gzipped.
+func UniProtEntriesBytes(data []byte, opts ...Option) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		cfg := newConfig(opts)
+		cfg.inputSize = int64(len(data))
+		entriesReader(bytes.NewReader(data), cfg)(yield)
+	}
+}
+
+// UniProtEntriesString returns an iterator over the UniProt entries in the
+// XML document data.
+func UniProtEntriesString(data string, opts ...Option) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		cfg := newConfig(opts)
+		cfg.inputSize = int64(len(data))
+		entriesReader(strings.NewReader(data), cfg)(yield)
+	}
+}
+
+// eachElement decodes the elements called name in r and calls fn for each of
+// them. An element over the configured maximum size is skipped and reported
+// to fn as an error wrapping ErrEntryTooLarge. eachElement returns nil at the
+// end of input, errStop if fn returns false, and the first read or decoding
+// error otherwise.
+func eachElement[T any](c *config, r io.Reader, name string, fn func(T, error) bool) error {
+	return eachStart(c, r, name, func(decoder *xml.Decoder, start xml.StartElement, offset int64) error {
+		var v T
+		var err error
+		if c.maxEntrySize > 0 {
+			err = decodeBounded(decoder, start, offset, c.maxEntrySize, &v)
+		} else {
+			err = decoder.DecodeElement(&v, &start)
+		}
+		if err != nil && !errors.Is(err, ErrEntryTooLarge) {
+			return err
+		}
+		if !fn(v, err) {
+			return errStop
+		}
+		return nil
+	})
+}
+
+// decodeBounded decodes the element opened by start into v like
+// DecodeElement, but gives up once the element has consumed more than limit
+// bytes from offset on. The tokens are buffered and decoded only when the
+// end tag is reached within the cap; otherwise the rest of the element is
+// skipped and an error wrapping ErrEntryTooLarge is returned.
+func decodeBounded(decoder *xml.Decoder, start xml.StartElement, offset, limit int64, v any) error {
+	tokens := []xml.Token{start.Copy()}
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+		if decoder.InputOffset()-offset > limit {
+			for ; depth > 0; depth-- {
+				if err := decoder.Skip(); err != nil {
+					return err
+				}
+			}
+			return fmt.Errorf("%w: %s at offset %d exceeds %d bytes", ErrEntryTooLarge, start.Name.Local, offset, limit)
+		}
+		tokens = append(tokens, xml.CopyToken(token))
+	}
+	return xml.NewTokenDecoder(&tokenSlice{tokens: tokens}).Decode(v)
+}
+
+// tokenSlice is an xml.TokenReader over buffered tokens.
+type tokenSlice struct {
+	tokens []xml.Token
+}
+
+func (t *tokenSlice) Token() (xml.Token, error) {
+	if len(t.tokens) == 0 {
+		return nil, io.EOF
+	}
+	token := t.tokens[0]
+	t.tokens = t.tokens[1:]
+	return token, nil
+}
+
+// eachStart calls fn for the start of each element called name in r, along
+// with the input offset of its opening "<". fn must consume the element
+// through its end tag. eachStart returns nil at the end of input and the
+// first error from reading or from fn otherwise.
+func eachStart(c *config, r io.Reader, name string, fn func(*xml.Decoder, xml.StartElement, int64) error) error {
+	decoder := c.newDecoder(r)
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, ok := token.(xml.Directive); ok && c.noDTD {
+			return ErrDTD
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != name {
+			continue
+		}
+		if err := fn(decoder, start, offset); err != nil {
+			return err
+		}
+	}
+}
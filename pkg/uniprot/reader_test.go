@@ -0,0 +1,46 @@
+package uniprot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// gzipMembers compresses each part as a separate gzip member and
+// concatenates them, as cat a.gz b.gz does.
+func gzipMembers(t *testing.T, parts ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, part := range parts {
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write([]byte(part)); err != nil {
+			t.Fatal(err)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestMultiMemberGzip(t *testing.T) {
+	data, err := os.ReadFile(sampleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(data)
+	want := readAll(t, UniProtEntries(sampleFile))
+
+	// Split inside the sequence of the first entry and inside the opening
+	// tag of the second.
+	first := strings.Index(doc, "VKAAWGKVGA") + 4
+	second := strings.LastIndex(doc, `<entry dataset="TrEMBL"`) + 10
+	path := writeTemp(t, "multi.xml.gz", gzipMembers(t, doc[:first], doc[first:second], doc[second:]))
+
+	if got := readAll(t, UniProtEntries(path)); !reflect.DeepEqual(got, want) {
+		t.Errorf("entries from a multi-member gzip differ:\n got %+v\nwant %+v", got, want)
+	}
+}
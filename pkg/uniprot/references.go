@@ -0,0 +1,157 @@
+package uniprot
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReferencesForScope returns the references with a scope containing the
+// given text, compared case-insensitively, so that "nucleotide sequence"
+// matches "NUCLEOTIDE SEQUENCE [MRNA]". The references are ordered by key.
+func (e Entry) ReferencesForScope(scope string) []Reference {
+	scope = strings.ToUpper(scope)
+	var refs []Reference
+	for _, ref := range e.Reference {
+		for _, s := range ref.Scope {
+			if strings.Contains(strings.ToUpper(s), scope) {
+				refs = append(refs, ref)
+				break
+			}
+		}
+	}
+	slices.SortStableFunc(refs, func(a, b Reference) int {
+		return compareKeys(a.Key, b.Key)
+	})
+	return refs
+}
+
+// compareKeys orders reference keys numerically when both are numbers, as
+// they are in UniProt XML, and lexically otherwise.
+func compareKeys(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return cmp.Compare(x, y)
+	}
+	return cmp.Compare(a, b)
+}
+
+// PrimaryPubMed returns the PubMed ID of the first reference whose citation
+// has one, usually the entry's main paper. It returns false if no reference
+// cites a PubMed ID.
+func (e Entry) PrimaryPubMed() (string, bool) {
+	for _, ref := range e.Reference {
+		for _, db := range ref.Citation.DbReference {
+			if db.Type == "PubMed" {
+				return db.ID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Reference renders the citation as a one-line bibliographic reference in
+// the style of UniProt's flat files, e.g.
+//
+//	Smith J., Doe A. Title of the paper. J. Mol. Biol. 168:321-331(1983).
+//
+// The source part follows the citation type: journal name, volume and
+// pages for articles; editors, book title, pages, publisher and city for
+// books; institute and country for theses; the number for patents; and the
+// database for submissions. The date follows in parentheses when it is
+// known. Citations of other types, or with no structured source, fall back
+// to the free text in Journal.
+func (c Citation) Reference() string {
+	var authors []string
+	for _, p := range c.AuthorList.Person {
+		authors = append(authors, p.Name)
+	}
+	for _, p := range c.AuthorList.Consortium {
+		authors = append(authors, p.Name)
+	}
+
+	pages := c.FirstPage
+	if c.LastPage != "" && c.LastPage != c.FirstPage {
+		pages += "-" + c.LastPage
+	}
+	var source string
+	switch c.Type {
+	case "journal article", "online journal article":
+		if c.Name != "" {
+			source = c.Name
+			if c.Volume != "" {
+				source += " " + c.Volume
+			}
+			if pages != "" {
+				source += ":" + pages
+			}
+			source = withDate(source, "", c.Date)
+		}
+	case "book":
+		if c.Name != "" {
+			var parts []string
+			if len(c.EditorList.Person) > 0 {
+				editors := make([]string, len(c.EditorList.Person))
+				for i, p := range c.EditorList.Person {
+					editors[i] = p.Name
+				}
+				parts = append(parts, "In: "+strings.Join(editors, ", ")+" (eds.); "+c.Name)
+			} else {
+				parts = append(parts, "In: "+c.Name)
+			}
+			if pages != "" {
+				parts = append(parts, "pp."+pages)
+			}
+			parts = appendNonEmpty(parts, c.Publisher, c.City)
+			source = withDate(strings.Join(parts, ", "), " ", c.Date)
+		}
+	case "thesis":
+		source = strings.Join(appendNonEmpty([]string{withDate("Thesis", " ", c.Date)}, c.Institute, c.Country), ", ")
+	case "patent":
+		if c.Number != "" {
+			source = strings.Join(appendNonEmpty([]string{"Patent number " + c.Number}, c.Date), ", ")
+		}
+	case "submission":
+		source = withDate("Submitted", " ", c.Date)
+		if c.DB != "" {
+			source += " to the " + c.DB
+		}
+	case "unpublished observations":
+		source = withDate("Unpublished observations", " ", c.Date)
+	}
+	if source == "" {
+		source = c.Journal.Value
+	}
+
+	var parts []string
+	for _, part := range []string{strings.Join(authors, ", "), c.Title, source} {
+		if part = strings.TrimSuffix(strings.TrimSpace(part), "."); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ". ") + "."
+}
+
+// withDate appends the date in parentheses to s, after sep, unless the date
+// is empty.
+func withDate(s, sep, date string) string {
+	if date == "" {
+		return s
+	}
+	return s + sep + "(" + date + ")"
+}
+
+// appendNonEmpty appends the non-empty values to list.
+func appendNonEmpty(list []string, values ...string) []string {
+	for _, v := range values {
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
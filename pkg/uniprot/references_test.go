@@ -0,0 +1,79 @@
+package uniprot
+
+import "testing"
+
+func TestCitationReference(t *testing.T) {
+	authors := AuthorList{
+		Person:     []Person{{Name: "Smith J."}, {Name: "Doe A."}},
+		Consortium: []Consortium{{Name: "The consortium"}},
+	}
+	tests := []struct {
+		name     string
+		citation Citation
+		want     string
+	}{
+		{
+			"journal",
+			Citation{Type: "journal article", Date: "1983", Name: "J. Mol. Biol.", Volume: "168", FirstPage: "321", LastPage: "331", Title: "Title of the paper.", AuthorList: authors},
+			"Smith J., Doe A., The consortium. Title of the paper. J. Mol. Biol. 168:321-331(1983).",
+		},
+		{
+			"journal without date",
+			Citation{Type: "journal article", Name: "J. Mol. Biol.", Volume: "168", FirstPage: "321"},
+			"J. Mol. Biol. 168:321.",
+		},
+		{
+			"book",
+			Citation{Type: "book", Date: "1990", Name: "Proteins", FirstPage: "1", LastPage: "20", Publisher: "Springer", City: "Berlin", EditorList: EditorList{Person: []Person{{Name: "Lee K."}}}},
+			"In: Lee K. (eds.); Proteins, pp.1-20, Springer, Berlin (1990).",
+		},
+		{
+			"book without date",
+			Citation{Type: "book", Name: "Proteins", Publisher: "Springer"},
+			"In: Proteins, Springer.",
+		},
+		{
+			"thesis",
+			Citation{Type: "thesis", Date: "2001", Institute: "Osaka University", Country: "Japan"},
+			"Thesis (2001), Osaka University, Japan.",
+		},
+		{
+			"thesis without date",
+			Citation{Type: "thesis", Institute: "Osaka University"},
+			"Thesis, Osaka University.",
+		},
+		{
+			"patent",
+			Citation{Type: "patent", Date: "2005", Number: "WO2005000001"},
+			"Patent number WO2005000001, 2005.",
+		},
+		{
+			"submission",
+			Citation{Type: "submission", Date: "JAN-2000", DB: "EMBL/GenBank/DDBJ databases"},
+			"Submitted (JAN-2000) to the EMBL/GenBank/DDBJ databases.",
+		},
+		{
+			"submission without date",
+			Citation{Type: "submission", DB: "PDB data bank"},
+			"Submitted to the PDB data bank.",
+		},
+		{
+			"unpublished without date",
+			Citation{Type: "unpublished observations"},
+			"Unpublished observations.",
+		},
+		{
+			"free text",
+			Citation{Type: "online journal article", Journal: Journal{Value: "Submitted (MAR-1999) to UniProtKB."}},
+			"Submitted (MAR-1999) to UniProtKB.",
+		},
+		{"empty", Citation{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.citation.Reference(); got != tt.want {
+				t.Errorf("Reference() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package uniprot
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// ReleaseInfo is the dataset metadata found in the header of an XML file.
+// Fields the file does not carry are empty.
+type ReleaseInfo struct {
+	// Root is the name of the root element, e.g. "uniprot" or "UniRef90".
+	Root           string
+	Namespace      string
+	SchemaLocation string
+	// Version and ReleaseDate are the version and releaseDate attributes
+	// of the root element, as written by UniRef, e.g. "2024_03" and
+	// "2024-05-29".
+	Version     string
+	ReleaseDate string
+	// Copyright is the text of a <copyright> element preceding the first
+	// entry.
+	Copyright string
+}
+
+// DatasetInfo reads the release metadata from the header of an XML file,
+// optionally gzipped, stopping at the first entry. UniProtKB files carry
+// no release in their header and place their copyright after the last
+// entry, so for them only the root and schema fields are set; their
+// release is published separately in reldate.txt.
+func DatasetInfo(filePath string) (ReleaseInfo, error) {
+	var info ReleaseInfo
+	r, err := Open(filePath)
+	if err != nil {
+		return info, err
+	}
+	defer r.Close()
+
+	cfg := defaultConfig()
+	decoder := cfg.newDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return info, nil
+		}
+		if err != nil {
+			return info, decodeError(filePath, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case info.Root == "":
+			info.Root = start.Name.Local
+			info.Namespace = start.Name.Space
+			for _, attr := range start.Attr {
+				switch attr.Name.Local {
+				case "schemaLocation":
+					info.SchemaLocation = attr.Value
+				case "version":
+					info.Version = attr.Value
+				case "releaseDate":
+					info.ReleaseDate = attr.Value
+				}
+			}
+		case start.Name.Local == "copyright":
+			var text string
+			if err := decoder.DecodeElement(&text, &start); err != nil {
+				return info, decodeError(filePath, err)
+			}
+			info.Copyright = strings.TrimSpace(text)
+		default:
+			return info, nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package uniprot
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// RNAEditing holds the edited positions of an "RNA editing" comment.
+type RNAEditing struct {
+	// LocationType is the locationType attribute of the comment, e.g.
+	// "Known" or "Not_applicable".
+	LocationType string
+	Positions    []int
+	Note         string
+}
+
+// UnmarshalXML decodes a comment element. RNA editing comments list one
+// location per edited position; these are collected into RNAEditing, while
+// Location holds the first of them as for other comment types.
+func (c *Comment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	// The alias drops the method; its name is capitalized because
+	// encoding/xml cannot set the fields of unexported embedded structs.
+	type Fields Comment
+	var shadow struct {
+		Fields
+		Locations    []Location `xml:"location"`
+		LocationType string     `xml:"locationType,attr"`
+	}
+	if err := d.DecodeElement(&shadow, &start); err != nil {
+		return err
+	}
+	*c = Comment(shadow.Fields)
+	c.XMLName = start.Name
+	if len(shadow.Locations) > 0 {
+		c.Location = shadow.Locations[0]
+	}
+	if c.Type != "RNA editing" {
+		return nil
+	}
+
+	editing := &RNAEditing{LocationType: shadow.LocationType}
+	for _, loc := range shadow.Locations {
+		if pos := loc.Position.Value; pos > 0 {
+			editing.Positions = append(editing.Positions, pos)
+		}
+	}
+	var notes []string
+	for _, t := range c.Text {
+		notes = append(notes, t.Value)
+	}
+	editing.Note = strings.Join(notes, " ")
+	c.RNAEditing = editing
+	return nil
+}
+
+// MarshalXML encodes a comment element. RNA editing comments are written
+// with their locationType and one location per edited position, the form
+// that UnmarshalXML reads back into RNAEditing.
+func (c Comment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type Fields Comment
+	if c.RNAEditing == nil {
+		return e.EncodeElement(Fields(c), start)
+	}
+	shadow := struct {
+		Fields
+		Locations    []Location `xml:"location"`
+		LocationType string     `xml:"locationType,attr,omitempty"`
+	}{Fields: Fields(c), LocationType: c.RNAEditing.LocationType}
+	for _, pos := range c.RNAEditing.Positions {
+		shadow.Locations = append(shadow.Locations, Location{Position: Position{Value: pos}})
+	}
+	return e.EncodeElement(shadow, start)
+}
+
+// RNAEditingPositions returns the edited positions of all the entry's RNA
+// editing comments, in document order.
+func (e Entry) RNAEditingPositions() []int {
+	var positions []int
+	for _, c := range e.Comment {
+		if c.RNAEditing != nil {
+			positions = append(positions, c.RNAEditing.Positions...)
+		}
+	}
+	return positions
+}
@@ -0,0 +1,196 @@
+package uniprot
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"unicode"
+)
+
+// stripSpace removes all white space from s.
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// UnmarshalXML decodes a sequence element and strips the white space that
+// wraps the residues in UniProt XML, so that Value holds exactly Length
+// residues.
+func (s *Sequence) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type sequence Sequence
+	var seq sequence
+	if err := d.DecodeElement(&seq, &start); err != nil {
+		return err
+	}
+	seq.Value = stripSpace(seq.Value)
+	*s = Sequence(seq)
+	return nil
+}
+
+// SequenceString returns the bare amino-acid sequence of the entry. Decoded
+// sequences are already free of white space; this also cleans up a Sequence
+// built by other means.
+func (e Entry) SequenceString() string {
+	return stripSpace(e.Sequence.Value)
+}
+
+// StandardAminoAcids lists the 20 standard residues in the order used by
+// CompositionVector.
+const StandardAminoAcids = "ACDEFGHIKLMNPQRSTVWY"
+
+// CompositionVector returns the frequencies of the 20 standard residues in
+// the sequence, in the order of StandardAminoAcids. Other letters are
+// ignored, and the frequencies sum to 1 unless no standard residue occurs.
+func (s Sequence) CompositionVector() [20]float64 {
+	var counts [20]float64
+	total := 0.0
+	for _, r := range s.Value {
+		if i := strings.IndexRune(StandardAminoAcids, unicode.ToUpper(r)); i >= 0 {
+			counts[i]++
+			total++
+		}
+	}
+	if total > 0 {
+		for i := range counts {
+			counts[i] /= total
+		}
+	}
+	return counts
+}
+
+// isStandard reports whether r is one of the 20 standard residues, in
+// either case.
+func isStandard(r rune) bool {
+	return strings.ContainsRune(StandardAminoAcids, unicode.ToUpper(r))
+}
+
+// NonStandardResidues maps each letter of the sequence that is not one of
+// the 20 standard residues, such as B, J, O, U, X, Z or "*", to its
+// 1-based positions in increasing order. Selenocysteine (U) and pyrrolysine
+// (O) are reported too, since tools restricted to the standard alphabet
+// reject them. The result is empty for a fully standard sequence.
+func (s Sequence) NonStandardResidues() map[rune][]int {
+	residues := make(map[rune][]int)
+	for i, r := range []rune(stripSpace(s.Value)) {
+		if !isStandard(r) {
+			residues[r] = append(residues[r], i+1)
+		}
+	}
+	return residues
+}
+
+// Sanitize returns the sequence with every residue reported by
+// NonStandardResidues replaced by replacement, typically 'X'. This
+// includes selenocysteine (U) and pyrrolysine (O); callers that want to
+// keep them, or to map U to C as some tools expect, should do so before
+// sanitizing. Standard residues are kept as they are.
+func (s Sequence) Sanitize(replacement byte) string {
+	return strings.Map(func(r rune) rune {
+		if isStandard(r) {
+			return r
+		}
+		return rune(replacement)
+	}, stripSpace(s.Value))
+}
+
+// CompositionDistance returns the Euclidean distance between two
+// composition vectors.
+func CompositionDistance(a, b [20]float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return math.Sqrt(sum)
+}
+
+// residueMass holds the average masses in daltons of the amino-acid
+// residues, as used for UniProt's computed sequence masses.
+var residueMass = map[rune]float64{
+	'A': 71.0788, 'R': 156.1875, 'N': 114.1038, 'D': 115.0886, 'C': 103.1388,
+	'E': 129.1155, 'Q': 128.1307, 'G': 57.0519, 'H': 137.1411, 'I': 113.1594,
+	'L': 113.1594, 'K': 128.1741, 'M': 131.1926, 'F': 147.1766, 'P': 97.1167,
+	'S': 87.0782, 'T': 101.1051, 'W': 186.2132, 'Y': 163.1760, 'V': 99.1326,
+	'U': 150.0388, 'O': 237.3018,
+}
+
+// waterMass is the average mass of the water added by the chain termini.
+const waterMass = 18.01524
+
+// MolecularWeight returns the average molecular mass in daltons of the
+// unmodified polypeptide. ok is false if the sequence is empty or contains
+// an ambiguous residue such as X, B or Z, whose mass is unknown.
+func (s Sequence) MolecularWeight() (mass float64, ok bool) {
+	for _, r := range stripSpace(s.Value) {
+		m, ok := residueMass[unicode.ToUpper(r)]
+		if !ok {
+			return 0, false
+		}
+		mass += m
+	}
+	if mass == 0 {
+		return 0, false
+	}
+	return mass + waterMass, true
+}
+
+// pKa values of the ionizable groups, from EMBOSS (Epk.dat).
+const (
+	pKaNTerm = 8.6
+	pKaCTerm = 3.6
+)
+
+// ionizable lists the side chains with their pKa values and the charge
+// they carry when ionized: positive when protonated, negative when
+// deprotonated.
+var ionizable = []struct {
+	residue rune
+	pKa     float64
+	charge  float64
+}{
+	{'K', 10.8, +1}, {'R', 12.5, +1}, {'H', 6.5, +1},
+	{'D', 3.9, -1}, {'E', 4.1, -1}, {'C', 8.5, -1}, {'Y', 10.1, -1},
+}
+
+// IsoelectricPoint returns the theoretical pI of the unmodified
+// polypeptide: the pH at which its net charge, summed by the
+// Henderson-Hasselbalch equation over both termini and the ionizable side
+// chains with the EMBOSS pKa set, is zero. The pH is found by bisection
+// over 0-14 to within 0.01 units. Residues other than the ionizable ones
+// do not contribute. An empty sequence yields 0.
+func (s Sequence) IsoelectricPoint() float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range stripSpace(s.Value) {
+		counts[unicode.ToUpper(r)]++
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	// fraction returns the ionized fraction of a group at the pH.
+	fraction := func(pH, pKa, charge float64) float64 {
+		return 1 / (1 + math.Pow(10, charge*(pH-pKa)))
+	}
+	netCharge := func(pH float64) float64 {
+		q := fraction(pH, pKaNTerm, +1) - fraction(pH, pKaCTerm, -1)
+		for _, g := range ionizable {
+			q += float64(counts[g.residue]) * g.charge * fraction(pH, g.pKa, g.charge)
+		}
+		return q
+	}
+	lo, hi := 0.0, 14.0
+	for hi-lo > 0.01 {
+		mid := (lo + hi) / 2
+		if netCharge(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
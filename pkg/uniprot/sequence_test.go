@@ -0,0 +1,29 @@
+package uniprot
+
+import "testing"
+
+func TestSequenceString(t *testing.T) {
+	for _, e := range sampleEntries(t) {
+		if seq := e.SequenceString(); len(seq) != e.Sequence.Length {
+			t.Errorf("%s: len(SequenceString()) = %d, want %d", e.Accession[0], len(seq), e.Sequence.Length)
+		}
+	}
+
+	e := decodeEntry(t, `<entry><accession>P1</accession>
+<sequence length="25">
+  MKTAY IIAKQ
+  RQISF VKSHF
+	SRQLE
+</sequence></entry>`)
+	if got, want := e.SequenceString(), "MKTAYIIAKQRQISFVKSHFSRQLE"; got != want {
+		t.Errorf("SequenceString() = %q, want %q", got, want)
+	}
+	if len(e.SequenceString()) != e.Sequence.Length {
+		t.Errorf("len(SequenceString()) = %d, want %d", len(e.SequenceString()), e.Sequence.Length)
+	}
+
+	built := Entry{Sequence: Sequence{Value: "MKT AY\nII\r\n"}}
+	if got := built.SequenceString(); got != "MKTAYII" {
+		t.Errorf("SequenceString() of a built sequence = %q, want %q", got, "MKTAYII")
+	}
+}
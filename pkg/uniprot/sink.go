@@ -0,0 +1,92 @@
+package uniprot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+)
+
+// EntrySink consumes entries one at a time, as fed by WriteMulti. Close
+// flushes whatever the sink buffers; it is called once, after the last
+// Write.
+type EntrySink interface {
+	Write(Entry) error
+	Close() error
+}
+
+// WriteMulti ranges over entries once and writes each entry to every sink
+// in turn, so several outputs are produced in a single pass. The first
+// error from entries or from a sink's Write stops the pass; the other
+// sinks do not receive the failing entry or any later one. All sinks are
+// closed in argument order at the end, whether or not the pass failed, so
+// the output written so far is flushed. WriteMulti returns the error that
+// stopped the pass joined with the errors of all Close calls.
+func WriteMulti(entries iter.Seq2[Entry, error], sinks ...EntrySink) error {
+	var errs []error
+	for entry, err := range entries {
+		if err == nil {
+			for _, sink := range sinks {
+				if err = sink.Write(entry); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+	}
+	for _, sink := range sinks {
+		errs = append(errs, sink.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// bufferedSink is an EntrySink writing through a buffer. Close flushes the
+// buffer but leaves the underlying writer open.
+type bufferedSink struct {
+	bw    *bufio.Writer
+	write func(Entry) error
+}
+
+func (s *bufferedSink) Write(entry Entry) error {
+	return s.write(entry)
+}
+
+func (s *bufferedSink) Close() error {
+	return s.bw.Flush()
+}
+
+// NewFASTASink returns a sink writing entries to w as WriteFASTA does.
+// Closing it flushes its buffer but does not close w.
+func NewFASTASink(w io.Writer) EntrySink {
+	bw := bufio.NewWriter(w)
+	return &bufferedSink{bw, func(entry Entry) error {
+		writeFASTA(bw, entry, FASTALineWidth)
+		return nil
+	}}
+}
+
+// NewJSONLSink returns a sink writing entries to w as WriteJSONL does with
+// the same options. Closing it flushes its buffer but does not close w.
+func NewJSONLSink(w io.Writer, opts ...WriteOption) EntrySink {
+	cfg := newWriteConfig(opts)
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	return &bufferedSink{bw, func(entry Entry) error {
+		return enc.Encode(cfg.prepare(entry))
+	}}
+}
+
+// NewTSVSink returns a sink writing one index line per entry to w, in the
+// format of WriteSearchSidecar. Closing it flushes its buffer but does not
+// close w.
+func NewTSVSink(w io.Writer) EntrySink {
+	bw := bufio.NewWriter(w)
+	return &bufferedSink{bw, func(entry Entry) error {
+		writeSidecarLine(bw, entry)
+		return nil
+	}}
+}
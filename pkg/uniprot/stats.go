@@ -0,0 +1,207 @@
+package uniprot
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+)
+
+// Histogram holds sequence-length counts keyed by bin index (length / bin width).
+type Histogram struct {
+	BinWidth  int
+	Bins      map[int]int
+	Count     int
+	MaxLength int
+}
+
+// LengthHistogram computes a sequence-length histogram over an XML file,
+// optionally gzipped, in one streaming pass. Only the length attribute of
+// each sequence is read; all other elements, including the residues
+// themselves, are skipped.
+func LengthHistogram(filePath string, binWidth int) (Histogram, error) {
+	if binWidth <= 0 {
+		return Histogram{}, fmt.Errorf("invalid bin width: %d", binWidth)
+	}
+	hist := Histogram{BinWidth: binWidth, Bins: make(map[int]int)}
+
+	r, err := Open(filePath)
+	if err != nil {
+		return hist, err
+	}
+	defer r.Close()
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return hist, nil
+		}
+		if err != nil {
+			return hist, decodeError(filePath, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "entry" {
+			continue
+		}
+		length, err := entrySequenceLength(decoder)
+		if err != nil {
+			return hist, decodeError(filePath, err)
+		}
+		hist.Bins[length/binWidth]++
+		hist.Count++
+		hist.MaxLength = max(hist.MaxLength, length)
+	}
+}
+
+// entrySequenceLength reads the children of the current entry element up to
+// its end tag and returns the length attribute of its sequence.
+func entrySequenceLength(decoder *xml.Decoder) (int, error) {
+	length := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "sequence" {
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "length" {
+						if length, err = strconv.Atoi(attr.Value); err != nil {
+							return 0, fmt.Errorf("invalid sequence length %q: %w", attr.Value, err)
+						}
+					}
+				}
+			}
+			if err := decoder.Skip(); err != nil {
+				return 0, err
+			}
+		case xml.EndElement:
+			return length, nil
+		}
+	}
+}
+
+// DbReferenceCoverage counts, per cross-referenced database, the number of
+// entries in a gzipped XML file with at least one reference to it.
+func DbReferenceCoverage(filePath string) (map[string]int, error) {
+	coverage := make(map[string]int)
+	for entry, err := range UniProtEntries(filePath) {
+		if err != nil {
+			return coverage, err
+		}
+		seen := make(map[string]bool)
+		for _, db := range entry.DbReference {
+			if !seen[db.Type] {
+				seen[db.Type] = true
+				coverage[db.Type]++
+			}
+		}
+	}
+	return coverage, nil
+}
+
+// Sample draws a uniform random sample of n entries from a gzipped XML file
+// in one pass, using reservoir sampling (Algorithm R). The same seed always
+// yields the same sample. Files with fewer than n entries are returned whole.
+func Sample(filePath string, n int, seed int64) ([]Entry, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid sample size: %d", n)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	sample := make([]Entry, 0, n)
+	i := int64(0)
+	for entry, err := range UniProtEntries(filePath) {
+		if err != nil {
+			return nil, err
+		}
+		if len(sample) < n {
+			sample = append(sample, entry)
+		} else if j := rng.Int63n(i + 1); j < int64(n) {
+			sample[j] = entry
+		}
+		i++
+	}
+	return sample, nil
+}
+
+// Unclassified is the TaxonomyRollup bucket for entries whose lineage is
+// too short for the requested level.
+const Unclassified = "(unclassified)"
+
+// TaxonomyRollup counts the entries of an XML file, optionally gzipped, by
+// the taxon at index level of their lineage (0 being the domain, e.g.
+// "Eukaryota"). Entries with a shorter lineage are counted as Unclassified.
+func TaxonomyRollup(filePath string, level int) (map[string]int, error) {
+	if level < 0 {
+		return nil, fmt.Errorf("invalid lineage level: %d", level)
+	}
+	counts := make(map[string]int)
+	for entry, err := range UniProtEntriesFields(filePath, FieldOrganism) {
+		if err != nil {
+			return counts, err
+		}
+		taxa := entry.Organism.Lineage.Taxon
+		if level < len(taxa) {
+			counts[taxa[level].Value]++
+		} else {
+			counts[Unclassified]++
+		}
+	}
+	return counts, nil
+}
+
+// ProteomeComposition counts the residues of all the sequences in an XML
+// file, optionally gzipped, and returns the count per residue letter along
+// with the total. Only the sequences are decoded, and letters are counted
+// as written, so lowercase letters in hand-made files count separately. On
+// error the counts cover the sequences read so far.
+func ProteomeComposition(filePath string) (map[rune]int64, int64, error) {
+	counts := make(map[rune]int64)
+	var ascii [128]int64
+	var total int64
+	var err error
+	for entry, e := range UniProtEntriesFields(filePath, FieldSequence) {
+		if e != nil {
+			err = e
+			break
+		}
+		for _, r := range entry.Sequence.Value {
+			if r < 128 {
+				ascii[r]++
+			} else {
+				counts[r]++
+			}
+			total++
+		}
+	}
+	for r, n := range ascii {
+		if n > 0 {
+			counts[rune(r)] = n
+		}
+	}
+	return counts, total, err
+}
+
+// DistinctOrganisms maps the NCBI taxonomy ID of every source organism in
+// an XML file, optionally gzipped, to its scientific name, in one
+// streaming pass that decodes only the organisms. The first non-empty name
+// seen for an ID is kept. Entries without a taxonomy ID are skipped, and
+// virus hosts (organismHost) are not included. On error the map covers the
+// entries read so far.
+func DistinctOrganisms(filePath string) (map[int]string, error) {
+	organisms := make(map[int]string)
+	for entry, err := range UniProtEntriesFields(filePath, FieldOrganism) {
+		if err != nil {
+			return organisms, err
+		}
+		id := entry.TaxID()
+		if id == 0 || organisms[id] != "" {
+			continue
+		}
+		organisms[id] = entry.ScientificName()
+	}
+	return organisms, nil
+}
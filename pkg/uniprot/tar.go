@@ -0,0 +1,58 @@
+package uniprot
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"iter"
+	"path"
+	"strings"
+)
+
+// UniProtEntriesTar returns an iterator over the entries stored in a tar
+// archive, optionally gzipped (.tar.gz), whose members are XML files holding
+// one or more entries, with or without the <uniprot> root element. Members
+// that are not regular files with an .xml or .xml.gz name are skipped.
+func UniProtEntriesTar(filePath string) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		r, err := Open(filePath)
+		if err != nil {
+			yield(Entry{}, err)
+			return
+		}
+		defer r.Close()
+
+		cfg := defaultConfig()
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				yield(Entry{}, decodeError(filePath, err))
+				return
+			}
+			if header.Typeflag != tar.TypeReg || !isXMLName(header.Name) {
+				continue
+			}
+			cfg.inputSize = header.Size
+			member, err := decompress(tr)
+			if err == nil {
+				err = eachElement(&cfg, member, "entry", yield)
+			}
+			if err == errStop {
+				return
+			}
+			if err != nil {
+				yield(Entry{}, fmt.Errorf("decoding %q in %q: %w", header.Name, filePath, err))
+				return
+			}
+		}
+	}
+}
+
+func isXMLName(name string) bool {
+	name = strings.ToLower(path.Base(name))
+	return strings.HasSuffix(name, ".xml") || strings.HasSuffix(name, ".xml.gz")
+}
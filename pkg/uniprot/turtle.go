@@ -0,0 +1,88 @@
+package uniprot
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const turtlePrefixes = `@prefix up: <http://purl.uniprot.org/core/> .
+@prefix uniprot: <http://purl.uniprot.org/uniprot/> .
+@prefix taxon: <http://purl.uniprot.org/taxonomy/> .
+@prefix keywords: <http://purl.uniprot.org/keywords/> .
+@prefix obo: <http://purl.obolibrary.org/obo/> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .
+
+`
+
+// turtleEscaper escapes the characters not allowed in Turtle string
+// literals.
+var turtleEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+func turtleString(s string) string {
+	return `"` + turtleEscaper.Replace(s) + `"`
+}
+
+// WriteTurtle writes the core facts of each entry as RDF in Turtle syntax,
+// using the predicates of the UniProt core ontology: the protein type,
+// review status, mnemonic, recommended name, organism (as a taxonomy URI),
+// sequence, keywords and GO terms (up:classifiedWith) and the other
+// cross-references (rdfs:seeAlso). Entries are written as they are read, so
+// memory use does not grow with the input.
+func WriteTurtle(w io.Writer, entries iter.Seq2[Entry, error]) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(turtlePrefixes)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		acc := entry.PrimaryAccession()
+		if acc == "" {
+			continue
+		}
+		writeTurtleEntry(bw, acc, entry)
+	}
+	return bw.Flush()
+}
+
+func writeTurtleEntry(bw *bufio.Writer, acc string, entry Entry) {
+	bw.WriteString("uniprot:" + acc + " a up:Protein")
+	triple := func(predicate, object string) {
+		bw.WriteString(" ;\n    " + predicate + " " + object)
+	}
+
+	triple("up:reviewed", strconv.FormatBool(entry.IsReviewed()))
+	if name := entry.EntryName(); name != "" {
+		triple("up:mnemonic", turtleString(name))
+	}
+	if full := entry.Protein.RecommendedName.FullName.Value; full != "" {
+		triple("up:recommendedName", "[ up:fullName "+turtleString(full)+" ]")
+	}
+	if taxID := entry.TaxID(); taxID > 0 {
+		triple("up:organism", "taxon:"+strconv.Itoa(taxID))
+	}
+	if seq := entry.SequenceString(); seq != "" {
+		triple("up:sequence", "[ a up:Simple_Sequence ; rdf:value "+turtleString(seq)+" ]")
+	}
+	for _, kw := range entry.Keyword {
+		if n, err := strconv.Atoi(strings.TrimPrefix(kw.ID, "KW-")); err == nil {
+			triple("up:classifiedWith", "keywords:"+strconv.Itoa(n))
+		}
+	}
+	for _, ref := range entry.DbReference {
+		if ref.ID == "" {
+			continue
+		}
+		if ref.Type == "GO" {
+			triple("up:classifiedWith", "obo:"+strings.Replace(ref.ID, ":", "_", 1))
+			continue
+		}
+		triple("rdfs:seeAlso", "<http://purl.uniprot.org/"+
+			url.PathEscape(strings.ToLower(ref.Type))+"/"+url.PathEscape(ref.ID)+">")
+	}
+	bw.WriteString(" .\n\n")
+}
@@ -1,16 +1,24 @@
 package uniprot
 
 import (
-	"compress/gzip"
 	"encoding/xml"
-	"fmt"
-	"io"
 	"iter"
-	"log"
-	"os"
 )
 
 // Define the structure for a single UniProt entry
+//
+// Elements missing from the input, as in derived exports without keywords,
+// features or comments, are left at their zero values. The methods of Entry
+// and the writers accept such partial entries, down to the zero Entry, and
+// return empty results for the missing parts rather than panicking.
+//
+// Repeated elements, such as alternative names, cross-references and
+// keywords, keep the order in which they were authored, and so do encoding
+// the entry with encoding/xml or WriteJSONL and decoding it again. Merge
+// keeps the order of base and appends the additions of overlay in their
+// order. Helpers returning sorted lists, such as FeaturesAt or Canonical,
+// sort copies and leave the entry as it is; only SortDbReferences reorders
+// an entry in place.
 type Entry struct {
 	XMLName          xml.Name         `xml:"entry"`
 	Dataset          string           `xml:"dataset,attr"`
@@ -30,6 +38,7 @@ type Entry struct {
 	ProteinExistence ProteinExistence `xml:"proteinExistence"`
 	Keyword          []Keyword        `xml:"keyword"`
 	Feature          []Feature        `xml:"feature"`
+	Evidence         []Evidence       `xml:"evidence"`
 	Sequence         Sequence         `xml:"sequence"`
 }
 
@@ -53,30 +62,35 @@ type RecommendedName struct {
 	XMLName   xml.Name    `xml:"recommendedName"`
 	FullName  FullName    `xml:"fullName"`
 	ShortName []ShortName `xml:"shortName"`
+	EcNumber  []string    `xml:"ecNumber"`
 }
 
 type AlternativeName struct {
 	XMLName   xml.Name    `xml:"alternativeName"`
 	FullName  FullName    `xml:"fullName"`
 	ShortName []ShortName `xml:"shortName"`
+	EcNumber  []string    `xml:"ecNumber"`
 }
 
 type SubmittedName struct {
 	XMLName   xml.Name    `xml:"submittedName"`
 	FullName  FullName    `xml:"fullName"`
 	ShortName []ShortName `xml:"shortName"`
+	EcNumber  []string    `xml:"ecNumber"`
 }
 
 type FullName struct {
-	XMLName  xml.Name   `xml:"fullName"`
-	Evidence []Evidence `xml:"evidence"`
-	Value    string     `xml:",chardata"`
+	XMLName      xml.Name   `xml:"fullName"`
+	EvidenceKeys string     `xml:"evidence,attr"`
+	Evidence     []Evidence `xml:"evidence"`
+	Value        string     `xml:",chardata"`
 }
 
 type ShortName struct {
-	XMLName  xml.Name   `xml:"shortName"`
-	Evidence []Evidence `xml:"evidence"`
-	Value    string     `xml:",chardata"`
+	XMLName      xml.Name   `xml:"shortName"`
+	EvidenceKeys string     `xml:"evidence,attr"`
+	Evidence     []Evidence `xml:"evidence"`
+	Value        string     `xml:",chardata"`
 }
 
 type Gene struct {
@@ -91,11 +105,10 @@ type GeneName struct {
 }
 
 type Organism struct {
-	XMLName        xml.Name       `xml:"organism"`
-	Name           []OrganismName `xml:"name"`
-	DbReference    []DbReference  `xml:"dbReference"`
-	Lineage        Lineage        `xml:"lineage"`
-	Classification []string       `xml:"classification"`
+	XMLName     xml.Name       `xml:"organism"`
+	Name        []OrganismName `xml:"name"`
+	DbReference []DbReference  `xml:"dbReference"`
+	Lineage     Lineage        `xml:"lineage"`
 }
 
 type OrganismName struct {
@@ -105,10 +118,19 @@ type OrganismName struct {
 }
 
 type DbReference struct {
-	XMLName  xml.Name   `xml:"dbReference"`
-	Type     string     `xml:"type,attr"`
-	ID       string     `xml:"id,attr"`
-	Evidence []Evidence `xml:"evidence"`
+	XMLName      xml.Name   `xml:"dbReference"`
+	Type         string     `xml:"type,attr"`
+	ID           string     `xml:"id,attr"`
+	EvidenceKeys string     `xml:"evidence,attr"`
+	Evidence     []Evidence `xml:"evidence"`
+	Property     []Property `xml:"property"`
+	Molecule     Molecule   `xml:"molecule"`
+}
+
+type Property struct {
+	XMLName xml.Name `xml:"property"`
+	Type    string   `xml:"type,attr"`
+	Value   string   `xml:"value,attr"`
 }
 
 type Lineage struct {
@@ -123,25 +145,28 @@ type Taxon struct {
 }
 
 type Sequence struct {
-	XMLName  xml.Name `xml:"sequence"`
-	Length   int      `xml:"length,attr"`
-	Mass     int      `xml:"mass,attr"`
-	Version  int      `xml:"version,attr"`
-	Modified string   `xml:"modified,attr"`
-	Checksum string   `xml:"checksum,attr"`
-	Value    string   `xml:",chardata"`
+	XMLName   xml.Name `xml:"sequence"`
+	Length    int      `xml:"length,attr"`
+	Mass      int      `xml:"mass,attr"`
+	Version   int      `xml:"version,attr"`
+	Modified  string   `xml:"modified,attr"`
+	Checksum  string   `xml:"checksum,attr"`
+	Precursor bool     `xml:"precursor,attr"`
+	Fragment  string   `xml:"fragment,attr"`
+	Value     string   `xml:",chardata"`
 }
 
 type Feature struct {
-	XMLName     xml.Name    `xml:"feature"`
-	Type        string      `xml:"type,attr"`
-	Id          string      `xml:"id,attr"`
-	Description string      `xml:"description,attr"`
-	Evidence    []Evidence  `xml:"evidence"`
-	Location    Location    `xml:"location"`
-	Ref         string      `xml:"ref,attr"`
-	Original    string      `xml:"original"`
-	Variation   []Variation `xml:"variation"`
+	XMLName      xml.Name    `xml:"feature"`
+	Type         string      `xml:"type,attr"`
+	Id           string      `xml:"id,attr"`
+	Description  string      `xml:"description,attr"`
+	EvidenceKeys string      `xml:"evidence,attr"`
+	Evidence     []Evidence  `xml:"evidence"`
+	Location     Location    `xml:"location"`
+	Ref          string      `xml:"ref,attr"`
+	Original     string      `xml:"original"`
+	Variation    []Variation `xml:"variation"`
 }
 
 type Location struct {
@@ -154,19 +179,19 @@ type Location struct {
 type Position struct {
 	XMLName xml.Name `xml:"position"`
 	Status  string   `xml:"status,attr"`
-	Value   int      `xml:",chardata"`
+	Value   int      `xml:"position,attr"`
 }
 
 type Begin struct {
 	XMLName  xml.Name `xml:"begin"`
 	Status   string   `xml:"status,attr"`
-	Position int      `xml:",chardata"`
+	Position int      `xml:"position,attr"`
 }
 
 type End struct {
 	XMLName  xml.Name `xml:"end"`
 	Status   string   `xml:"status,attr"`
-	Position int      `xml:",chardata"`
+	Position int      `xml:"position,attr"`
 }
 
 type Variation struct {
@@ -176,9 +201,15 @@ type Variation struct {
 }
 
 type Evidence struct {
-	XMLName xml.Name `xml:"evidence"`
-	Type    string   `xml:"type,attr"`
-	Key     string   `xml:"key,attr"`
+	XMLName xml.Name       `xml:"evidence"`
+	Type    string         `xml:"type,attr"`
+	Key     string         `xml:"key,attr"`
+	Source  EvidenceSource `xml:"source"`
+}
+
+type EvidenceSource struct {
+	XMLName     xml.Name      `xml:"source"`
+	DbReference []DbReference `xml:"dbReference"`
 }
 
 type OrganismHost struct {
@@ -189,12 +220,14 @@ type OrganismHost struct {
 }
 
 type GeneLocation struct {
-	XMLName     xml.Name         `xml:"geneLocation"`
-	Gene        string           `xml:"gene,attr"`
-	Evidence    []Evidence       `xml:"evidence"`
-	Name        GeneLocationName `xml:"name"`
-	Chromosome  string           `xml:"chromosome"`
-	MapPosition string           `xml:"mapPosition"`
+	XMLName      xml.Name         `xml:"geneLocation"`
+	Type         string           `xml:"type,attr"`
+	Gene         string           `xml:"gene,attr"`
+	EvidenceKeys string           `xml:"evidence,attr"`
+	Evidence     []Evidence       `xml:"evidence"`
+	Name         GeneLocationName `xml:"name"`
+	Chromosome   string           `xml:"chromosome"`
+	MapPosition  string           `xml:"mapPosition"`
 }
 
 type GeneLocationName struct {
@@ -215,23 +248,46 @@ type Reference struct {
 	DbReference []DbReference   `xml:"dbReference"`
 }
 
+// Citation is the bibliographic part of a reference. Which attributes are
+// set depends on Type: journal articles and books have Name (the journal
+// or book title), Volume and pages, books also Publisher and City, theses
+// Institute and Country, patents Number, and submissions DB.
 type Citation struct {
 	XMLName     xml.Name      `xml:"citation"`
 	Type        string        `xml:"type,attr"`
-	Date        string        `xml:"date"`
+	Date        string        `xml:"date,attr"`
+	Name        string        `xml:"name,attr"`
+	Volume      string        `xml:"volume,attr"`
+	FirstPage   string        `xml:"first,attr"`
+	LastPage    string        `xml:"last,attr"`
+	Publisher   string        `xml:"publisher,attr"`
+	City        string        `xml:"city,attr"`
+	Institute   string        `xml:"institute,attr"`
+	Country     string        `xml:"country,attr"`
+	Number      string        `xml:"number,attr"`
+	DB          string        `xml:"db,attr"`
 	Title       string        `xml:"title"`
 	Journal     Journal       `xml:"journal"`
+	EditorList  EditorList    `xml:"editorList"`
 	AuthorList  AuthorList    `xml:"authorList"`
 	DbReference []DbReference `xml:"dbReference"`
 }
 
+// Journal holds the free-text citation of sources that give no structured
+// one, such as the RL line of flat files.
 type Journal struct {
 	XMLName xml.Name `xml:"journal"`
 	Value   string   `xml:",chardata"`
 }
 
 type AuthorList struct {
-	XMLName xml.Name `xml:"authorList"`
+	XMLName    xml.Name     `xml:"authorList"`
+	Person     []Person     `xml:"person"`
+	Consortium []Consortium `xml:"consortium"`
+}
+
+type EditorList struct {
+	XMLName xml.Name `xml:"editorList"`
 	Person  []Person `xml:"person"`
 }
 
@@ -240,6 +296,11 @@ type Person struct {
 	Name    string   `xml:"name,attr"`
 }
 
+type Consortium struct {
+	XMLName xml.Name `xml:"consortium"`
+	Name    string   `xml:"name,attr"`
+}
+
 type Source struct {
 	XMLName     xml.Name      `xml:"source"`
 	Organism    Organism      `xml:"organism"`
@@ -265,21 +326,53 @@ type OrganismSection struct {
 type Comment struct {
 	XMLName           xml.Name          `xml:"comment"`
 	Type              string            `xml:"type,attr"`
+	EvidenceKeys      string            `xml:"evidence,attr"`
 	Evidence          []Evidence        `xml:"evidence"`
 	Text              []Text            `xml:"text"`
-	Molecule          string            `xml:"molecule,attr"`
+	Molecule          Molecule          `xml:"molecule"`
+	Mass              float64           `xml:"mass,attr"`
+	MassError         string            `xml:"error,attr"`
+	Method            string            `xml:"method,attr"`
 	Location          Location          `xml:"location"`
 	Reaction          Reaction          `xml:"reaction"`
 	Enzyme            Enzyme            `xml:"enzyme"`
-	Ph                Ph                `xml:"ph"`
-	Temperature       Temperature       `xml:"temperature"`
-	KineticParameters KineticParameters `xml:"kineticParameters"`
+	Ph                Ph                `xml:"phDependence"`
+	Temperature       Temperature       `xml:"temperatureDependence"`
+	KineticParameters KineticParameters `xml:"kinetics"`
+	Event             []Event           `xml:"event"`
+	Isoform           []Isoform         `xml:"isoform"`
+	RNAEditing        *RNAEditing       `xml:"-"`
+}
+
+type Event struct {
+	XMLName xml.Name `xml:"event"`
+	Type    string   `xml:"type,attr"`
+}
+
+type Isoform struct {
+	XMLName  xml.Name        `xml:"isoform"`
+	ID       []string        `xml:"id"`
+	Name     []string        `xml:"name"`
+	Sequence IsoformSequence `xml:"sequence"`
+}
+
+type IsoformSequence struct {
+	XMLName xml.Name `xml:"sequence"`
+	Type    string   `xml:"type,attr"`
+	Ref     string   `xml:"ref,attr"`
+}
+
+type Molecule struct {
+	XMLName xml.Name `xml:"molecule"`
+	ID      string   `xml:"id,attr"`
+	Value   string   `xml:",chardata"`
 }
 
 type Text struct {
-	XMLName  xml.Name   `xml:"text"`
-	Evidence []Evidence `xml:"evidence"`
-	Value    string     `xml:",chardata"`
+	XMLName      xml.Name   `xml:"text"`
+	EvidenceKeys string     `xml:"evidence,attr"`
+	Evidence     []Evidence `xml:"evidence"`
+	Value        string     `xml:",chardata"`
 }
 
 type Reaction struct {
@@ -295,29 +388,30 @@ type Enzyme struct {
 }
 
 type Ph struct {
-	XMLName xml.Name `xml:"ph"`
-	Value   string   `xml:",chardata"`
+	XMLName xml.Name `xml:"phDependence"`
+	Value   string   `xml:"text"`
 }
 
 type Temperature struct {
-	XMLName xml.Name `xml:"temperature"`
-	Value   string   `xml:",chardata"`
+	XMLName xml.Name `xml:"temperatureDependence"`
+	Value   string   `xml:"text"`
 }
 
 type KineticParameters struct {
-	XMLName xml.Name `xml:"kineticParameters"`
-	Km      []Km     `xml:"km"`
-	Vmax    []Vmax   `xml:"vmax"`
+	XMLName xml.Name `xml:"kinetics"`
+	Km      []Km     `xml:"KM"`
+	Vmax    []Vmax   `xml:"Vmax"`
+	Text    string   `xml:"text"`
 }
 
 type Km struct {
-	XMLName xml.Name `xml:"km"`
+	XMLName xml.Name `xml:"KM"`
 	Value   string   `xml:",chardata"`
 	Unit    string   `xml:"unit,attr"`
 }
 
 type Vmax struct {
-	XMLName xml.Name `xml:"vmax"`
+	XMLName xml.Name `xml:"Vmax"`
 	Value   string   `xml:",chardata"`
 	Unit    string   `xml:"unit,attr"`
 }
@@ -328,53 +422,21 @@ type ProteinExistence struct {
 }
 
 type Keyword struct {
-	XMLName  xml.Name   `xml:"keyword"`
-	Evidence []Evidence `xml:"evidence"`
-	Value    string     `xml:",chardata"`
+	XMLName      xml.Name   `xml:"keyword"`
+	ID           string     `xml:"id,attr"`
+	EvidenceKeys string     `xml:"evidence,attr"`
+	Evidence     []Evidence `xml:"evidence"`
+	Value        string     `xml:",chardata"`
 }
 
 // UniProtEntries returns an iterator over UniProt entries from a gzipped XML file.
+//
+// Decoding does not depend on the order of the sub-elements of an entry or
+// of its nested elements: input from producers that deviate from the
+// schema's sequence, e.g. with <gene> after <organism> or keywords mixed
+// with features, decodes to the same Entry as canonical input, with
+// repeated elements kept in document order. The same holds for
+// UniProtEntriesFields.
 func UniProtEntries(filePath string) iter.Seq2[Entry, error] {
-	file, err := os.Open(filePath)
-	if err != nil {
-		log.Fatal(filePath)
-	}
-
-	gzipReader, err := gzip.NewReader(file)
-	if err != nil {
-		file.Close()
-		log.Fatal("gzip error")
-	}
-
-	decoder := xml.NewDecoder(gzipReader)
-	yieldedRoot := false
-
-	return func(yield func(Entry, error) bool) {
-		defer file.Close()
-		defer gzipReader.Close()
-		for {
-			token, err := decoder.Token()
-			if err != nil {
-				if err == io.EOF {
-					return
-				}
-				log.Fatalf("Error decoding XML: %v\n", err)
-				return
-			}
-
-			if start, ok := token.(xml.StartElement); ok {
-				if start.Name.Local == "uniprot" {
-					yieldedRoot = true
-					continue // Move to the next token
-				}
-				if start.Name.Local == "entry" && yieldedRoot {
-					var entry Entry
-					err := decoder.DecodeElement(&entry, &start)
-					if !yield(entry, err) {
-						return // Stop if the consumer doesn't want more
-					}
-				}
-			}
-		}
-	}
+	return UniProtEntriesWith(filePath)
 }
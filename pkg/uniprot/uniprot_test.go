@@ -0,0 +1,295 @@
+package uniprot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sampleFile holds two Swiss-Prot-like entries, P69905 and Q00001, with a
+// little of every element the package decodes.
+const sampleFile = "testdata/sample.xml"
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<uniprot xmlns="http://uniprot.org/uniprot">
+`
+
+// wrapEntries returns a UniProt XML document holding the given entry
+// elements.
+func wrapEntries(entries ...string) string {
+	return xmlHeader + strings.Join(entries, "\n") + "\n</uniprot>\n"
+}
+
+// decodeEntry decodes the single entry element in entry.
+func decodeEntry(t testing.TB, entry string) Entry {
+	t.Helper()
+	entries := readAll(t, UniProtEntries(gzipTemp(t, "entry.xml.gz", []byte(wrapEntries(entry)))))
+	if len(entries) != 1 {
+		t.Fatalf("decoded %d entries, want 1", len(entries))
+	}
+	return entries[0]
+}
+
+// readAll collects the entries of seq, failing the test on error.
+func readAll[T any](t testing.TB, seq func(func(T, error) bool)) []T {
+	t.Helper()
+	var all []T
+	for v, err := range seq {
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, v)
+	}
+	return all
+}
+
+// writeTemp writes data to a file in a temporary directory and returns its
+// path.
+func writeTemp(t testing.TB, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// gzipTemp is like writeTemp but compresses data with gzip.
+func gzipTemp(t testing.TB, name string, data []byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return writeTemp(t, name, buf.Bytes())
+}
+
+// sampleEntries decodes the entries of sampleFile.
+func sampleEntries(t testing.TB) []Entry {
+	t.Helper()
+	data, err := os.ReadFile(sampleFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return readAll(t, UniProtEntries(gzipTemp(t, "sample.xml.gz", data)))
+}
+
+// sampleEntry returns the first entry of sampleFile.
+func sampleEntry(t testing.TB) Entry {
+	t.Helper()
+	return sampleEntries(t)[0]
+}
+
+// orderedEntry follows the element order of the UniProt schema, and
+// shuffledEntry holds the same content in another order: the gene after
+// the organism, keywords mixed with features, and reordered children of
+// nested elements. Repeated elements keep their relative order.
+const (
+	orderedEntry = `<entry dataset="Swiss-Prot" created="1986-07-21" modified="2024-07-24" version="250">
+<accession>P69905</accession>
+<accession>P01922</accession>
+<name>HBA_HUMAN</name>
+<protein>
+  <recommendedName><fullName>Hemoglobin subunit alpha</fullName><shortName>HbA</shortName></recommendedName>
+  <alternativeName><fullName>Alpha-globin</fullName></alternativeName>
+</protein>
+<gene><name type="primary">HBA1</name></gene>
+<organism>
+  <name type="scientific">Homo sapiens</name>
+  <dbReference type="NCBI Taxonomy" id="9606"/>
+  <lineage><taxon>Eukaryota</taxon><taxon>Metazoa</taxon></lineage>
+</organism>
+<reference key="1">
+  <citation type="journal article" date="1981" name="Nature" volume="291" first="100" last="110">
+    <title>The alpha globin gene.</title>
+    <authorList><person name="Smith A."/><person name="Jones B."/></authorList>
+    <dbReference type="PubMed" id="111"/>
+  </citation>
+  <scope>NUCLEOTIDE SEQUENCE</scope>
+</reference>
+<comment type="function"><text>Involved in oxygen transport.</text></comment>
+<dbReference type="PDB" id="1A00">
+  <property type="method" value="X-ray"/>
+  <molecule id="P69905-1"/>
+</dbReference>
+<dbReference type="Pfam" id="PF00042"><property type="entry name" value="Globin"/></dbReference>
+<proteinExistence type="evidence at protein level"/>
+<keyword id="KW-0002">3D-structure</keyword>
+<keyword id="KW-0349">Heme</keyword>
+<feature type="chain" id="PRO_0000052653" description="Hemoglobin subunit alpha">
+  <location><begin position="2"/><end position="20"/></location>
+</feature>
+<feature type="sequence variant" description="In Tottori.">
+  <original>V</original><variation>G</variation>
+  <location><position position="3"/></location>
+</feature>
+<evidence type="ECO:0000269" key="1"><source><dbReference type="PubMed" id="333"/></source></evidence>
+<sequence length="20" mass="2200" checksum="ABCDEF" modified="2007-01-23" version="2">MVLSPADKTNVKAAWGKVGA</sequence>
+</entry>`
+
+	shuffledEntry = `<entry version="250" modified="2024-07-24" created="1986-07-21" dataset="Swiss-Prot">
+<name>HBA_HUMAN</name>
+<accession>P69905</accession>
+<organism>
+  <lineage><taxon>Eukaryota</taxon><taxon>Metazoa</taxon></lineage>
+  <dbReference type="NCBI Taxonomy" id="9606"/>
+  <name type="scientific">Homo sapiens</name>
+</organism>
+<gene><name type="primary">HBA1</name></gene>
+<sequence version="2" modified="2007-01-23" checksum="ABCDEF" mass="2200" length="20">MVLSPADKTNVKAAWGKVGA</sequence>
+<keyword id="KW-0002">3D-structure</keyword>
+<feature type="chain" id="PRO_0000052653" description="Hemoglobin subunit alpha">
+  <location><end position="20"/><begin position="2"/></location>
+</feature>
+<keyword id="KW-0349">Heme</keyword>
+<feature type="sequence variant" description="In Tottori.">
+  <location><position position="3"/></location>
+  <variation>G</variation><original>V</original>
+</feature>
+<accession>P01922</accession>
+<protein>
+  <alternativeName><fullName>Alpha-globin</fullName></alternativeName>
+  <recommendedName><shortName>HbA</shortName><fullName>Hemoglobin subunit alpha</fullName></recommendedName>
+</protein>
+<evidence key="1" type="ECO:0000269"><source><dbReference id="333" type="PubMed"/></source></evidence>
+<dbReference type="PDB" id="1A00">
+  <molecule id="P69905-1"/>
+  <property type="method" value="X-ray"/>
+</dbReference>
+<proteinExistence type="evidence at protein level"/>
+<comment type="function"><text>Involved in oxygen transport.</text></comment>
+<reference key="1">
+  <scope>NUCLEOTIDE SEQUENCE</scope>
+  <citation last="110" first="100" volume="291" name="Nature" date="1981" type="journal article">
+    <dbReference type="PubMed" id="111"/>
+    <authorList><person name="Smith A."/><person name="Jones B."/></authorList>
+    <title>The alpha globin gene.</title>
+  </citation>
+</reference>
+<dbReference type="Pfam" id="PF00042"><property type="entry name" value="Globin"/></dbReference>
+</entry>`
+)
+
+func TestDecodingIgnoresElementOrder(t *testing.T) {
+	ordered := writeTemp(t, "ordered.xml", []byte(wrapEntries(orderedEntry)))
+	shuffled := writeTemp(t, "shuffled.xml", []byte(wrapEntries(shuffledEntry)))
+
+	want := readAll(t, UniProtEntries(ordered))
+	if len(want) != 1 || len(want[0].Accession) != 2 || len(want[0].Feature) != 2 || len(want[0].Reference) != 1 {
+		t.Fatalf("ordered entry decoded as %+v", want)
+	}
+	if got := readAll(t, UniProtEntries(shuffled)); !reflect.DeepEqual(got, want) {
+		t.Errorf("UniProtEntries of reordered input:\n got %+v\nwant %+v", got, want)
+	}
+	for _, fields := range []FieldMask{FieldAll, FieldAccession | FieldGene | FieldKeyword | FieldFeature | FieldSequence} {
+		want := readAll(t, UniProtEntriesFields(ordered, fields))
+		if got := readAll(t, UniProtEntriesFields(shuffled, fields)); !reflect.DeepEqual(got, want) {
+			t.Errorf("UniProtEntriesFields(%#x) of reordered input:\n got %+v\nwant %+v", fields, got, want)
+		}
+	}
+}
+
+// minimalEntry has nothing but an accession and a sequence.
+const minimalEntry = `<entry><accession>P1</accession><sequence length="5">MKTAY</sequence></entry>`
+
+// callAll calls every method of *e with zero arguments and returns the
+// results by method name. A panicking method fails the test.
+func callAll(t *testing.T, e Entry) map[string][]reflect.Value {
+	t.Helper()
+	results := make(map[string][]reflect.Value)
+	v := reflect.ValueOf(&e)
+	for i := range v.NumMethod() {
+		name := v.Type().Method(i).Name
+		m := v.Method(i)
+		args := make([]reflect.Value, m.Type().NumIn())
+		for j := range args {
+			args[j] = reflect.Zero(m.Type().In(j))
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s panicked: %v", name, r)
+				}
+			}()
+			results[name] = m.Call(args)
+		}()
+	}
+	return results
+}
+
+func TestMinimalEntryAccessors(t *testing.T) {
+	callAll(t, Entry{})
+
+	e := decodeEntry(t, minimalEntry)
+	// The accessors below have something to report even for a minimal
+	// entry; they are checked one by one. Errors are ignored as results.
+	want := map[string]any{
+		"PrimaryAccession": "P1",
+		"SequenceString":   "MKTAY",
+		"HasSequence":      true,
+		"MaskSequence":     "MKTAY",
+		"FASTAHeader":      "tr|P1|",
+		"WebURL":           "https://www.uniprot.org/uniprotkb/P1/entry",
+		"FASTAURL":         "https://rest.uniprot.org/uniprotkb/P1.fasta",
+		"XMLURL":           "https://rest.uniprot.org/uniprotkb/P1.xml",
+	}
+	errorType := reflect.TypeFor[error]()
+	for name, out := range callAll(t, e) {
+		switch name {
+		case "Canonical", "Flatten", "MatureSequence":
+			continue
+		}
+		for _, v := range out {
+			if v.Type() == errorType {
+				continue
+			}
+			if w, ok := want[name]; ok {
+				if got := v.Interface(); got != w {
+					t.Errorf("%s = %v, want %v", name, got, w)
+				}
+				continue
+			}
+			empty := v.IsZero() || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0
+			if !empty {
+				t.Errorf("%s = %v, want zero", name, v.Interface())
+			}
+		}
+	}
+
+	if seq, ok := e.MatureSequence(); seq != "MKTAY" || ok {
+		t.Errorf("MatureSequence = %q, %t; want MKTAY, false", seq, ok)
+	}
+	if r := e.Flatten(); r.Accession != "P1" || r.Length != 5 || r.Reviewed {
+		t.Errorf("Flatten = %+v", r)
+	}
+	if c := e.Canonical(); !strings.Contains(c, `accession: "P1"`) || !strings.Contains(c, "MKTAY") {
+		t.Errorf("Canonical =\n%s", c)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	entries := make([]Entry, 5)
+	var sizes []int
+	for _, batch := range readAll(t, Batch(seqOf(entries), 2)) {
+		sizes = append(sizes, len(batch))
+	}
+	if want := []int{2, 2, 1}; !slices.Equal(sizes, want) {
+		t.Errorf("batch sizes %v, want %v", sizes, want)
+	}
+	for batch, err := range Batch(seqOf(entries), 0) {
+		if err == nil || batch != nil {
+			t.Errorf("Batch with size 0 yielded %d entries, error %v", len(batch), err)
+		}
+		return
+	}
+	t.Error("Batch with size 0 yielded nothing")
+}
@@ -0,0 +1,184 @@
+package uniprot
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// URLOption configures UniProtEntriesURL.
+type URLOption func(*urlConfig)
+
+type urlConfig struct {
+	ctx        context.Context
+	client     *http.Client
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+// WithContext sets the context governing the requests and the retry waits.
+func WithContext(ctx context.Context) URLOption {
+	return func(c *urlConfig) { c.ctx = ctx }
+}
+
+// WithHTTPClient sets the client used for the requests (http.DefaultClient by default).
+func WithHTTPClient(client *http.Client) URLOption {
+	return func(c *urlConfig) { c.client = client }
+}
+
+// WithMaxRetries sets how many times a failed stream is retried (3 by default).
+func WithMaxRetries(n int) URLOption {
+	return func(c *urlConfig) { c.maxRetries = n }
+}
+
+// WithBaseDelay sets the delay before the first retry (1s by default).
+// The delay doubles with every further attempt.
+func WithBaseDelay(d time.Duration) URLOption {
+	return func(c *urlConfig) { c.baseDelay = d }
+}
+
+// transientError marks a failure that is worth retrying.
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string { return e.err.Error() }
+func (e transientError) Unwrap() error { return e.err }
+
+// transientReader remembers the last read error of the response body, so
+// that network failures can be told apart from malformed XML.
+type transientReader struct {
+	r   io.Reader
+	err error
+}
+
+func (t *transientReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	if err != nil && err != io.EOF {
+		t.err = err
+	}
+	return n, err
+}
+
+// UniProtEntriesURL returns an iterator over UniProt entries streamed from url,
+// such as a UniProt REST stream endpoint. Gzipped responses are decompressed
+// transparently.
+//
+// Network errors and 5xx responses are retried with exponential backoff. An
+// interrupted stream cannot be resumed where it broke off, so a retry requests
+// url again from the start and skips the entries already yielded; this relies
+// on the server returning the same entries in the same order.
+func UniProtEntriesURL(url string, opts ...URLOption) iter.Seq2[Entry, error] {
+	cfg := urlConfig{
+		ctx:        context.Background(),
+		client:     http.DefaultClient,
+		maxRetries: 3,
+		baseDelay:  time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(yield func(Entry, error) bool) {
+		yielded := 0
+		for attempt := 0; ; attempt++ {
+			skip := yielded
+			canceled := false
+			err := cfg.stream(url, func(entry Entry) bool {
+				if cfg.ctx.Err() != nil {
+					canceled = true
+					return false
+				}
+				if skip > 0 {
+					skip--
+					return true
+				}
+				yielded++
+				return yield(entry, nil)
+			})
+			if err == nil || err == errStop && !canceled {
+				return
+			}
+			// Cancellation closes the body under the decoder, so the stream
+			// fails with a read error; report the cancellation instead.
+			if canceled || cfg.ctx.Err() != nil {
+				yield(Entry{}, cfg.ctx.Err())
+				return
+			}
+			var transient transientError
+			if !errors.As(err, &transient) || attempt >= cfg.maxRetries {
+				yield(Entry{}, err)
+				return
+			}
+			select {
+			case <-cfg.ctx.Done():
+				yield(Entry{}, cfg.ctx.Err())
+				return
+			case <-time.After(cfg.baseDelay << attempt):
+			}
+		}
+	}
+}
+
+// UniProtEntriesURLContext is UniProtEntriesURL governed by ctx. Cancelling
+// ctx ends the iteration, including mid-stream and during retry waits, and
+// closes the response body right away; the last value yielded is then
+// ctx.Err().
+func UniProtEntriesURLContext(ctx context.Context, url string, opts ...URLOption) iter.Seq2[Entry, error] {
+	return UniProtEntriesURL(url, append(opts, WithContext(ctx))...)
+}
+
+// stream performs a single request for url and decodes its entries. The
+// response is decompressed according to its Content-Encoding, and gzipped
+// payloads are detected by their magic number as well.
+func (c *urlConfig) stream(url string, fn func(Entry) bool) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if c.ctx.Err() != nil {
+			return err
+		}
+		return transientError{err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("GET %s: %s", url, resp.Status)
+		if resp.StatusCode >= 500 {
+			return transientError{err}
+		}
+		return err
+	}
+
+	stop := context.AfterFunc(c.ctx, func() { resp.Body.Close() })
+	defer stop()
+
+	body := &transientReader{r: resp.Body}
+	var r io.Reader = body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		r, err = gzip.NewReader(r)
+		if err != nil {
+			return transientError{err}
+		}
+	}
+	r, err = decompress(r)
+	if err == nil {
+		cfg := defaultConfig()
+		err = eachElement(&cfg, r, "entry", func(entry Entry, _ error) bool {
+			return fn(entry)
+		})
+	}
+	if err != nil && err != errStop && body.err != nil && c.ctx.Err() == nil {
+		return transientError{err}
+	}
+	return err
+}
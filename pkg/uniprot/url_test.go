@@ -0,0 +1,134 @@
+package uniprot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const urlEntries = `<entry><accession>P1</accession></entry>
+<entry><accession>P2</accession></entry>
+<entry><accession>P3</accession></entry>`
+
+func TestUniProtEntriesURLRetry(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, wrapEntries(urlEntries))
+	}))
+	defer srv.Close()
+
+	var accs []string
+	for e, err := range UniProtEntriesURL(srv.URL, WithBaseDelay(time.Millisecond)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		accs = append(accs, e.PrimaryAccession())
+	}
+	if fmt.Sprint(accs) != "[P1 P2 P3]" {
+		t.Errorf("entries %v, want [P1 P2 P3]", accs)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d requests, want 2", n)
+	}
+}
+
+func TestUniProtEntriesURLNoRetryOn4xx(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	for _, err := range UniProtEntriesURL(srv.URL, WithBaseDelay(time.Millisecond)) {
+		if err == nil {
+			t.Fatal("got an entry from a 404 response")
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
+
+// detachedTransport sends requests without their context, so that only
+// closing the response body can interrupt a read, as with transports that
+// do not watch the context once the headers have arrived.
+type detachedTransport struct{}
+
+func (detachedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return http.DefaultTransport.RoundTrip(req.WithContext(context.WithoutCancel(req.Context())))
+}
+
+func TestUniProtEntriesURLCancelMidStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	received := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, xmlHeader+"<entry><accession>P1</accession></entry>\n")
+		w.(http.Flusher).Flush()
+		// Cancel while the client waits for the rest of the stream.
+		<-received
+		cancel()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var accs []string
+	var last error
+	client := &http.Client{Transport: detachedTransport{}}
+	for e, err := range UniProtEntriesURLContext(ctx, srv.URL, WithHTTPClient(client), WithBaseDelay(time.Millisecond)) {
+		if err != nil {
+			last = err
+			break
+		}
+		accs = append(accs, e.PrimaryAccession())
+		close(received)
+	}
+	if fmt.Sprint(accs) != "[P1]" {
+		t.Errorf("entries %v, want [P1]", accs)
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Errorf("error %v, want context.Canceled", last)
+	}
+}
+
+func TestUniProtEntriesURLCancelDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+		time.AfterFunc(10*time.Millisecond, cancel)
+	}))
+	defer srv.Close()
+
+	start := time.Now()
+	var last error
+	for _, err := range UniProtEntriesURLContext(ctx, srv.URL, WithBaseDelay(time.Hour)) {
+		last = err
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Errorf("error %v, want context.Canceled", last)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("cancellation took %v", elapsed)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests, want 1", n)
+	}
+}
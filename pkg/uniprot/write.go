@@ -0,0 +1,212 @@
+package uniprot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GzipWriter returns a writer compressing to w. It must be closed to write
+// the gzip trailer; closing it does not close w.
+func GzipWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, gzip.DefaultCompression)
+}
+
+// WriteFile creates filePath and passes it to write, gzip-compressing the
+// output when the path ends in ".gz". The gzip trailer is written and the
+// file closed before WriteFile returns, e.g.
+//
+//	err := WriteFile("edges.tsv.gz", func(w io.Writer) error {
+//		return WriteEdgeList(w, entries)
+//	})
+func WriteFile(filePath string, write func(io.Writer) error) (err error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	if !strings.HasSuffix(filePath, ".gz") {
+		return write(file)
+	}
+	gz, err := GzipWriter(file)
+	if err != nil {
+		return err
+	}
+	if err := write(gz); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteOption configures the entry writers WriteJSONL, WriteXML and
+// NewJSONLSink.
+type WriteOption func(*writeConfig)
+
+type writeConfig struct {
+	sortDbReferences bool
+}
+
+func newWriteConfig(opts []WriteOption) writeConfig {
+	var cfg writeConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithSortedDbReferences makes the writer sort the cross-references of each
+// entry as SortDbReferences does before writing it, for output that diffs
+// cleanly across releases. The entries passed in are left unchanged.
+func WithSortedDbReferences() WriteOption {
+	return func(c *writeConfig) { c.sortDbReferences = true }
+}
+
+// prepare returns entry as it is to be written.
+func (c *writeConfig) prepare(entry Entry) Entry {
+	if c.sortDbReferences {
+		entry.DbReference = slices.Clone(entry.DbReference)
+		entry.SortDbReferences()
+	}
+	return entry
+}
+
+// WriteXML writes the entries as a UniProt XML document with a <uniprot>
+// root element and one <entry> element per line. The entries are not
+// indented, since white space added around the residues of a <variation>
+// would change them. Decoding the output gives entries equal to the written
+// ones under Canonical. Elements of the model are written even when empty,
+// so the output is not meant for validation against the UniProt schema.
+func WriteXML(w io.Writer, entries iter.Seq2[Entry, error], opts ...WriteOption) error {
+	cfg := newWriteConfig(opts)
+	bw := bufio.NewWriter(w)
+	bw.WriteString(xml.Header)
+	enc := xml.NewEncoder(bw)
+	root := xml.StartElement{
+		Name: xml.Name{Local: "uniprot"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "http://uniprot.org/uniprot"}},
+	}
+	if err := enc.EncodeToken(root); err != nil {
+		return err
+	}
+	for entry, err := range entries {
+		if err == nil {
+			err = enc.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		bw.WriteByte('\n')
+		if err := enc.Encode(cfg.prepare(entry)); err != nil {
+			return err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return err
+	}
+	bw.WriteByte('\n')
+	if err := enc.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	bw.WriteByte('\n')
+	return bw.Flush()
+}
+
+// WriteEdgeList writes one "accession\tdbType\tdbID" line per cross-reference
+// of each entry, for import into graph databases. Within an entry the edges
+// are de-duplicated and sorted by database type and ID, so the output is
+// stable across runs.
+func WriteEdgeList(w io.Writer, entries iter.Seq2[Entry, error]) error {
+	bw := bufio.NewWriter(w)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		refs := slices.Clone(entry.DbReference)
+		slices.SortFunc(refs, compareDbReferences)
+		refs = slices.CompactFunc(refs, func(a, b DbReference) bool {
+			return a.Type == b.Type && a.ID == b.ID
+		})
+		acc := entry.PrimaryAccession()
+		for _, ref := range refs {
+			bw.WriteString(acc + "\t" + ref.Type + "\t" + ref.ID + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// tsvField replaces the tabs and line breaks in s with spaces.
+func tsvField(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '\t', '\n', '\r':
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// WriteFeatureTSV writes the feature table of each entry as tab-separated
+// lines "accession\ttype\tbegin\tend\tdescription\tftid", with -1 for
+// unknown positions. See Entry.FeatureTable.
+func WriteFeatureTSV(w io.Writer, entries iter.Seq2[Entry, error]) error {
+	bw := bufio.NewWriter(w)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		for _, row := range entry.FeatureTable() {
+			bw.WriteString(row.Accession + "\t" + row.Type + "\t" +
+				strconv.Itoa(row.Begin) + "\t" + strconv.Itoa(row.End) + "\t" +
+				tsvField(row.Description) + "\t" + row.FTId + "\n")
+		}
+	}
+	return bw.Flush()
+}
+
+// listField joins values with ";" for a TSV column, after replacing the
+// semicolons inside them with commas and their tabs and line breaks with
+// spaces, so the list splits back unambiguously.
+func listField(values []string) string {
+	fields := make([]string, len(values))
+	for i, v := range values {
+		fields[i] = strings.ReplaceAll(tsvField(v), ";", ",")
+	}
+	return strings.Join(fields, ";")
+}
+
+// WriteSearchSidecar writes one tab-separated annotation line per entry,
+// "accession\tgeneName\tproteinName\tkeywords\tGO", with the keywords and
+// GO IDs joined by ";". Written next to a FASTA file of the same entries,
+// it annotates the hits of a sequence search by accession.
+func WriteSearchSidecar(w io.Writer, entries iter.Seq2[Entry, error]) error {
+	bw := bufio.NewWriter(w)
+	for entry, err := range entries {
+		if err != nil {
+			return err
+		}
+		writeSidecarLine(bw, entry)
+	}
+	return bw.Flush()
+}
+
+// writeSidecarLine writes the WriteSearchSidecar line of entry.
+func writeSidecarLine(bw *bufio.Writer, entry Entry) {
+	bw.WriteString(entry.PrimaryAccession() + "\t" +
+		tsvField(entry.GeneName()) + "\t" + tsvField(entry.ProteinName()) + "\t" +
+		listField(entry.Keywords()) + "\t" + listField(entry.DbReferenceIDs("GO")) + "\n")
+}
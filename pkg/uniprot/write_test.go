@@ -0,0 +1,144 @@
+package uniprot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// seqOf returns an iterator over entries without errors.
+func seqOf(entries []Entry) iter.Seq2[Entry, error] {
+	return func(yield func(Entry, error) bool) {
+		for _, e := range entries {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+// dbReferenceIDs lists the "type:id" of the entry's cross-references.
+func dbReferenceIDs(e Entry) []string {
+	return mapSlice(e.DbReference, func(r DbReference) string { return r.Type + ":" + r.ID })
+}
+
+func TestWriteXMLRoundTrip(t *testing.T) {
+	entries := readAll(t, UniProtEntries(sampleFile))
+	var buf bytes.Buffer
+	if err := WriteXML(&buf, UniProtEntries(sampleFile)); err != nil {
+		t.Fatal(err)
+	}
+	decoded := readAll(t, UniProtEntriesString(buf.String()))
+	if len(decoded) != len(entries) {
+		t.Fatalf("decoded %d entries, want %d", len(decoded), len(entries))
+	}
+	for i := range entries {
+		if got, want := decoded[i].Canonical(), entries[i].Canonical(); got != want {
+			t.Errorf("entry %d after WriteXML:\n%s\nwant\n%s", i, got, want)
+		}
+	}
+	if got := decoded[0].RNAEditingPositions(); !slices.Equal(got, []int{5, 9}) {
+		t.Errorf("RNA editing positions after WriteXML = %v, want [5 9]", got)
+	}
+}
+
+func TestWithSortedDbReferences(t *testing.T) {
+	entries := readAll(t, UniProtEntries(sampleFile))
+	original := dbReferenceIDs(entries[0])
+	sorted := slices.Clone(original)
+	slices.Sort(sorted)
+	if slices.Equal(original, sorted) {
+		t.Fatal("sample cross-references are already sorted")
+	}
+
+	var jsonl, xmlDoc, sink bytes.Buffer
+	if err := WriteJSONL(&jsonl, seqOf(entries), WithSortedDbReferences()); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteXML(&xmlDoc, seqOf(entries), WithSortedDbReferences()); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteMulti(seqOf(entries), NewJSONLSink(&sink, WithSortedDbReferences())); err != nil {
+		t.Fatal(err)
+	}
+	outputs := map[string][]Entry{
+		"WriteJSONL":   readAll(t, EntriesFromJSONL(&jsonl)),
+		"WriteXML":     readAll(t, UniProtEntriesString(xmlDoc.String())),
+		"NewJSONLSink": readAll(t, EntriesFromJSONL(&sink)),
+	}
+	for name, out := range outputs {
+		if got := dbReferenceIDs(out[0]); !slices.Equal(got, sorted) {
+			t.Errorf("%s wrote cross-references %v, want %v", name, got, sorted)
+		}
+	}
+	if got := dbReferenceIDs(entries[0]); !slices.Equal(got, original) {
+		t.Errorf("writers reordered the input entry: %v", got)
+	}
+
+	jsonl.Reset()
+	if err := WriteJSONL(&jsonl, seqOf(entries)); err != nil {
+		t.Fatal(err)
+	}
+	if got := dbReferenceIDs(readAll(t, EntriesFromJSONL(strings.NewReader(jsonl.String())))[0]); !slices.Equal(got, original) {
+		t.Errorf("WriteJSONL without options wrote %v, want %v", got, original)
+	}
+}
+
+// repeatedOrder lists the alternative names, cross-references and keywords
+// of e in order.
+func repeatedOrder(e Entry) []string {
+	var order []string
+	for _, n := range e.Protein.AlternativeName {
+		order = append(order, n.FullName.Value)
+	}
+	order = append(order, dbReferenceIDs(e)...)
+	return append(order, e.Keywords()...)
+}
+
+func TestSerializationKeepsOrder(t *testing.T) {
+	e := decodeEntry(t, `<entry dataset="Swiss-Prot">
+<accession>P1</accession>
+<protein>
+<recommendedName><fullName>Main</fullName></recommendedName>
+<alternativeName><fullName>Zeta</fullName></alternativeName>
+<alternativeName><fullName>Alpha</fullName></alternativeName>
+<alternativeName><fullName>Mu</fullName></alternativeName>
+</protein>
+<dbReference type="PDB" id="3XYZ"/>
+<dbReference type="EMBL" id="X00001"/>
+<dbReference type="PDB" id="1ABC"/>
+<keyword id="KW-0002">Zinc</keyword>
+<keyword id="KW-0001">Acetylation</keyword>
+<keyword id="KW-0003">Metal-binding</keyword>
+<sequence length="3">MKT</sequence>
+</entry>`)
+	want := []string{"Zeta", "Alpha", "Mu", "PDB:3XYZ", "EMBL:X00001", "PDB:1ABC", "Zinc", "Acetylation", "Metal-binding"}
+	if got := repeatedOrder(e); !slices.Equal(got, want) {
+		t.Fatalf("decoded order %v, want %v", got, want)
+	}
+
+	marshaled, err := xml.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonl, xmlDoc bytes.Buffer
+	if err := WriteJSONL(&jsonl, seqOf([]Entry{e})); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteXML(&xmlDoc, seqOf([]Entry{e})); err != nil {
+		t.Fatal(err)
+	}
+	outputs := map[string][]Entry{
+		"xml.Marshal": readAll(t, UniProtEntriesString(wrapEntries(string(marshaled)))),
+		"WriteJSONL":  readAll(t, EntriesFromJSONL(&jsonl)),
+		"WriteXML":    readAll(t, UniProtEntriesString(xmlDoc.String())),
+	}
+	for name, out := range outputs {
+		if got := repeatedOrder(out[0]); !slices.Equal(got, want) {
+			t.Errorf("order after %s = %v, want %v", name, got, want)
+		}
+	}
+}
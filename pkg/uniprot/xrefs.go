@@ -0,0 +1,129 @@
+package uniprot
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// PropertyValue returns the value of the cross-reference property of the
+// given type, such as "entry name" or "gene ID", or "" if it is absent.
+func (r DbReference) PropertyValue(propertyType string) string {
+	for _, p := range r.Property {
+		if p.Type == propertyType {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// DbReferencesForMolecule returns the cross-references scoped by their
+// <molecule> element to the given molecule, typically an isoform such as
+// "P69905-1". The molecule is identified by the element's id attribute, or
+// by its text if it has none. An empty molecule selects the
+// cross-references that apply to the entry as a whole.
+func (e Entry) DbReferencesForMolecule(molecule string) []DbReference {
+	var refs []DbReference
+	for _, ref := range e.DbReference {
+		if cmp.Or(ref.Molecule.ID, ref.Molecule.Value) == molecule {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// SortDbReferences sorts the entry's cross-references in place by database
+// type and then by ID, keeping the original order of equal references. The
+// XML release orders them by database only loosely, so sorting before
+// writing gives output that diffs cleanly across releases; WriteJSONL and
+// WriteXML do so on a copy with WithSortedDbReferences.
+func (e *Entry) SortDbReferences() {
+	slices.SortStableFunc(e.DbReference, compareDbReferences)
+}
+
+// compareDbReferences orders cross-references by database type and ID.
+func compareDbReferences(a, b DbReference) int {
+	return cmp.Or(cmp.Compare(a.Type, b.Type), cmp.Compare(a.ID, b.ID))
+}
+
+// domainDatabases are the protein family and domain databases reported by
+// DomainFamilies.
+var domainDatabases = map[string]bool{
+	"CDD":      true,
+	"Gene3D":   true,
+	"HAMAP":    true,
+	"InterPro": true,
+	"NCBIfam":  true,
+	"PANTHER":  true,
+	"Pfam":     true,
+	"PIRSF":    true,
+	"PRINTS":   true,
+	"PROSITE":  true,
+	"SFLD":     true,
+	"SMART":    true,
+	"SUPFAM":   true,
+}
+
+// DomainRef is a cross-reference to a protein family or domain signature.
+type DomainRef struct {
+	DB   string
+	ID   string
+	Name string
+}
+
+// DomainFamilies returns the entry's cross-references to family and domain
+// databases such as InterPro, Pfam, PROSITE and SMART, with the family name
+// taken from the "entry name" property.
+func (e Entry) DomainFamilies() []DomainRef {
+	var domains []DomainRef
+	for _, ref := range e.DbReference {
+		if domainDatabases[ref.Type] {
+			domains = append(domains, DomainRef{
+				DB:   ref.Type,
+				ID:   ref.ID,
+				Name: ref.PropertyValue("entry name"),
+			})
+		}
+	}
+	return domains
+}
+
+// GenomicMapping links an entry to a transcript and gene of a genome
+// annotation.
+type GenomicMapping struct {
+	// DB is the database type, e.g. "Ensembl", "EnsemblBacteria" or
+	// "RefSeq".
+	DB           string
+	TranscriptID string
+	ProteinID    string
+	// GeneID is empty for RefSeq, whose cross-references carry no gene.
+	GeneID string
+}
+
+// GenomicMappings returns one mapping per Ensembl-family or RefSeq
+// cross-reference of the entry, so an entry encoded by several transcripts
+// yields several mappings. Ensembl cross-references are keyed by the
+// transcript, with the "protein sequence ID" and "gene ID" properties;
+// RefSeq ones are keyed by the protein, with the transcript in the
+// "nucleotide sequence ID" property.
+func (e Entry) GenomicMappings() []GenomicMapping {
+	var mappings []GenomicMapping
+	for _, ref := range e.DbReference {
+		switch {
+		case ref.Type == "RefSeq":
+			mappings = append(mappings, GenomicMapping{
+				DB:           ref.Type,
+				TranscriptID: ref.PropertyValue("nucleotide sequence ID"),
+				ProteinID:    ref.ID,
+			})
+		case strings.HasPrefix(ref.Type, "Ensembl"):
+			mappings = append(mappings, GenomicMapping{
+				DB:           ref.Type,
+				TranscriptID: ref.ID,
+				ProteinID:    ref.PropertyValue("protein sequence ID"),
+				GeneID:       ref.PropertyValue("gene ID"),
+			})
+		}
+	}
+	return mappings
+}
@@ -0,0 +1,80 @@
+package uniprot
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDomainFamilies(t *testing.T) {
+	e := decodeEntry(t, `<entry>
+<accession>P69905</accession>
+<dbReference type="PDB" id="1A00">
+  <property type="method" value="X-ray"/>
+</dbReference>
+<dbReference type="InterPro" id="IPR000971">
+  <property type="entry name" value="Globin"/>
+</dbReference>
+<dbReference type="Pfam" id="PF00042">
+  <property type="entry name" value="Globin"/>
+  <property type="match status" value="1"/>
+</dbReference>
+<dbReference type="GO" id="GO:0005833">
+  <property type="term" value="C:hemoglobin complex"/>
+</dbReference>
+<dbReference type="PROSITE" id="PS01033">
+  <property type="entry name" value="GLOBIN"/>
+  <property type="match status" value="1"/>
+</dbReference>
+</entry>`)
+	want := []DomainRef{
+		{DB: "InterPro", ID: "IPR000971", Name: "Globin"},
+		{DB: "Pfam", ID: "PF00042", Name: "Globin"},
+		{DB: "PROSITE", ID: "PS01033", Name: "GLOBIN"},
+	}
+	if got := e.DomainFamilies(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DomainFamilies() = %+v, want %+v", got, want)
+	}
+	if got := (Entry{}).DomainFamilies(); got != nil {
+		t.Errorf("DomainFamilies() of an empty entry = %+v, want nil", got)
+	}
+}
+
+func TestDbReferencesForMolecule(t *testing.T) {
+	e := decodeEntry(t, `<entry>
+<accession>P04637</accession>
+<dbReference type="PDB" id="1A1U">
+  <property type="method" value="NMR"/>
+</dbReference>
+<dbReference type="RefSeq" id="NP_000537.3">
+  <property type="nucleotide sequence ID" value="NM_000546.5"/>
+  <molecule id="P04637-1"/>
+</dbReference>
+<dbReference type="RefSeq" id="NP_001119584.1">
+  <property type="nucleotide sequence ID" value="NM_001126112.2"/>
+  <molecule id="P04637-1"/>
+</dbReference>
+<dbReference type="Ensembl" id="ENST00000413465.6">
+  <property type="protein sequence ID" value="ENSP00000410739.2"/>
+  <property type="gene ID" value="ENSG00000141510.19"/>
+  <molecule>P04637-2</molecule>
+</dbReference>
+</entry>`)
+	ids := func(refs []DbReference) []string {
+		return mapSlice(refs, func(r DbReference) string { return r.ID })
+	}
+	tests := map[string][]string{
+		"P04637-1": {"NP_000537.3", "NP_001119584.1"},
+		"P04637-2": {"ENST00000413465.6"},
+		"":         {"1A1U"},
+		"P04637-3": nil,
+	}
+	for molecule, want := range tests {
+		if got := ids(e.DbReferencesForMolecule(molecule)); !slices.Equal(got, want) {
+			t.Errorf("DbReferencesForMolecule(%q) = %v, want %v", molecule, got, want)
+		}
+	}
+	if got := e.DbReference[1].Molecule.ID; got != "P04637-1" {
+		t.Errorf("decoded molecule ID %q, want P04637-1", got)
+	}
+}
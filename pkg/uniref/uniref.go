@@ -0,0 +1,95 @@
+package uniref
+
+import (
+	"encoding/xml"
+	"iter"
+	"strconv"
+
+	"github.com/arkinjo/TogoProt2/pkg/uniprot"
+)
+
+// Cluster is a UniRef cluster: a representative sequence and the members
+// clustered with it.
+type Cluster struct {
+	XMLName              xml.Name   `xml:"entry"`
+	ID                   string     `xml:"id,attr"`
+	Updated              string     `xml:"updated,attr"`
+	Name                 string     `xml:"name"`
+	Property             []Property `xml:"property"`
+	RepresentativeMember Member     `xml:"representativeMember"`
+	Member               []Member   `xml:"member"`
+}
+
+type Member struct {
+	DbReference DbReference      `xml:"dbReference"`
+	Sequence    uniprot.Sequence `xml:"sequence"`
+}
+
+type DbReference struct {
+	XMLName  xml.Name   `xml:"dbReference"`
+	Type     string     `xml:"type,attr"`
+	ID       string     `xml:"id,attr"`
+	Property []Property `xml:"property"`
+}
+
+type Property struct {
+	XMLName xml.Name `xml:"property"`
+	Type    string   `xml:"type,attr"`
+	Value   string   `xml:"value,attr"`
+}
+
+func propertyValue(props []Property, propType string) string {
+	for _, p := range props {
+		if p.Type == propType {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+// Accession returns the UniProtKB accession of the member, or its UniParc
+// ID for members that are not in UniProtKB.
+func (m Member) Accession() string {
+	if acc := propertyValue(m.DbReference.Property, "UniProtKB accession"); acc != "" {
+		return acc
+	}
+	return m.DbReference.ID
+}
+
+// RepresentativeAccession returns the accession of the representative member.
+func (c Cluster) RepresentativeAccession() string {
+	return c.RepresentativeMember.Accession()
+}
+
+// Members returns the accessions of all members of the cluster, starting
+// with the representative.
+func (c Cluster) Members() []string {
+	members := []string{c.RepresentativeAccession()}
+	for _, m := range c.Member {
+		members = append(members, m.Accession())
+	}
+	return members
+}
+
+// MemberCount returns the member count declared by the cluster.
+func (c Cluster) MemberCount() int {
+	n, _ := strconv.Atoi(propertyValue(c.Property, "member count"))
+	return n
+}
+
+// CommonTaxon returns the name of the lowest taxon shared by all members.
+func (c Cluster) CommonTaxon() string {
+	return propertyValue(c.Property, "common taxon")
+}
+
+// CommonTaxonID returns the NCBI taxonomy identifier of the common taxon.
+func (c Cluster) CommonTaxonID() int {
+	id, _ := strconv.Atoi(propertyValue(c.Property, "common taxon ID"))
+	return id
+}
+
+// UniRefEntries returns an iterator over the clusters in a UniRef XML file,
+// optionally gzipped.
+func UniRefEntries(filePath string) iter.Seq2[Cluster, error] {
+	return uniprot.Elements[Cluster](filePath, "entry")
+}